@@ -0,0 +1,9 @@
+// Package assets embeds static files shipped with pixgen.
+package assets
+
+import _ "embed"
+
+// PixLogo is the official Pix logo (1024x1024 PNG), suitable for qrcode.Logo.
+//
+//go:embed logo-pix.png
+var PixLogo []byte
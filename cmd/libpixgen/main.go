@@ -0,0 +1,158 @@
+// Command libpixgen builds the Pix generator as a C shared library:
+//
+//	go build -buildmode=c-shared -o libpixgen.so ./cmd/libpixgen
+//
+// cgo writes the matching libpixgen.h next to the library (use .dylib or .dll on macOS and
+// Windows). Requests are the JSON bodies of the REST service (POST /pix), without profiles.
+// Every pointer returned by the library, including the error messages stored in *err, is
+// allocated with malloc and must be released with pixgen_free; on failure the functions
+// return NULL (or 0) and, when err is not NULL, store the message in *err.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/thiagozs/go-pixgen/internal/bindings"
+	"github.com/thiagozs/go-pixgen/pix"
+)
+
+func main() {}
+
+// pixgen_generate builds the payload and QR Code of a JSON request and returns the JSON
+// response: payload, qrCode (base64), format, qrMetadata, kind, txid and parsed.
+//
+//export pixgen_generate
+func pixgen_generate(request *C.char, err **C.char) *C.char {
+	defer recoverError(err)
+	clearError(err)
+
+	req, e := decodeRequest(request)
+	if e != nil {
+		setError(err, e)
+		return nil
+	}
+	resp, e := bindings.Generate(req)
+	if e != nil {
+		setError(err, e)
+		return nil
+	}
+	return marshalJSON(resp, err)
+}
+
+// pixgen_parse parses and checks the CRC of a Pix copia-e-cola payload and returns it as
+// JSON.
+//
+//export pixgen_parse
+func pixgen_parse(payload *C.char, err **C.char) *C.char {
+	defer recoverError(err)
+	clearError(err)
+
+	if payload == nil {
+		setError(err, errors.New("payload is required"))
+		return nil
+	}
+	parsed, e := pix.ParsePayload(C.GoString(payload))
+	if e != nil {
+		setError(err, e)
+		return nil
+	}
+	return marshalJSON(parsed, err)
+}
+
+// pixgen_validate returns 1 when the JSON request would generate a valid payload and 0
+// otherwise, with the reason in *err.
+//
+//export pixgen_validate
+func pixgen_validate(request *C.char, err **C.char) C.int {
+	defer recoverError(err)
+	clearError(err)
+
+	req, e := decodeRequest(request)
+	if e != nil {
+		setError(err, e)
+		return 0
+	}
+	if v := bindings.Validate(req); !v.Valid {
+		setError(err, errors.New(v.Error))
+		return 0
+	}
+	return 1
+}
+
+// pixgen_png renders the QR Code of a JSON request as a PNG image and stores its length in
+// *size.
+//
+//export pixgen_png
+func pixgen_png(request *C.char, size *C.size_t, err **C.char) *C.uchar {
+	defer recoverError(err)
+	clearError(err)
+
+	if size == nil {
+		setError(err, errors.New("size is required"))
+		return nil
+	}
+	*size = 0
+	req, e := decodeRequest(request)
+	if e != nil {
+		setError(err, e)
+		return nil
+	}
+	data, e := bindings.PNG(req)
+	if e != nil {
+		setError(err, e)
+		return nil
+	}
+	*size = C.size_t(len(data))
+	return (*C.uchar)(C.CBytes(data))
+}
+
+// pixgen_free releases a string, PNG buffer or error message returned by the library.
+//
+//export pixgen_free
+func pixgen_free(p unsafe.Pointer) {
+	C.free(p)
+}
+
+func decodeRequest(request *C.char) (bindings.Request, error) {
+	if request == nil {
+		return bindings.Request{}, errors.New("request is required")
+	}
+	return bindings.DecodeRequest([]byte(C.GoString(request)))
+}
+
+func marshalJSON(v interface{}, err **C.char) *C.char {
+	data, e := json.Marshal(v)
+	if e != nil {
+		setError(err, e)
+		return nil
+	}
+	return C.CString(string(data))
+}
+
+func clearError(err **C.char) {
+	if err != nil {
+		*err = nil
+	}
+}
+
+func setError(err **C.char, e error) {
+	if err != nil {
+		*err = C.CString(e.Error())
+	}
+}
+
+// recoverError keeps a panic from unwinding into the C caller, which would abort the
+// process; the function then returns its zero value.
+func recoverError(err **C.char) {
+	if r := recover(); r != nil {
+		setError(err, fmt.Errorf("internal error: %v", r))
+	}
+}
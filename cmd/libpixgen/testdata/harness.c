@@ -0,0 +1,122 @@
+/*
+ * Test harness for libpixgen. Build the library and run it with
+ *
+ *   make lib-test
+ *
+ * or by hand:
+ *
+ *   go build -buildmode=c-shared -o bin/lib/libpixgen.so ./cmd/libpixgen
+ *   cc -Ibin/lib -o bin/lib/harness cmd/libpixgen/testdata/harness.c -Lbin/lib -lpixgen -Wl,-rpath,'$ORIGIN'
+ *   bin/lib/harness
+ */
+#include <stdio.h>
+#include <string.h>
+
+#include "libpixgen.h"
+
+static const char *request =
+    "{\"pixKey\":\"+5511999999999\",\"merchantName\":\"Loja Exemplo\","
+    "\"merchantCity\":\"Sao Paulo\",\"amount\":\"12.50\",\"txid\":\"PEDIDO123\"}";
+
+static int failures = 0;
+
+#define CHECK(cond, ...)                                                     \
+    do {                                                                     \
+        if (!(cond)) {                                                       \
+            fprintf(stderr, "FAIL %s:%d: ", __FILE__, __LINE__);             \
+            fprintf(stderr, __VA_ARGS__);                                    \
+            fprintf(stderr, "\n");                                           \
+            failures++;                                                      \
+        }                                                                    \
+    } while (0)
+
+/* extract_payload copies the "payload" value of a generate response into out. */
+static int extract_payload(const char *json, char *out, size_t size) {
+    const char *start = strstr(json, "\"payload\":\"");
+    const char *end;
+    if (start == NULL) {
+        return 0;
+    }
+    start += strlen("\"payload\":\"");
+    end = strchr(start, '"');
+    if (end == NULL || (size_t)(end - start) >= size) {
+        return 0;
+    }
+    memcpy(out, start, end - start);
+    out[end - start] = '\0';
+    return 1;
+}
+
+static void test_generate_and_parse(void) {
+    char *err = NULL;
+    char payload[512];
+    char *parsed;
+    char *resp = pixgen_generate((char *)request, &err);
+
+    CHECK(resp != NULL && err == NULL, "generate: %s", err ? err : "no response");
+    if (resp == NULL) {
+        pixgen_free(err);
+        return;
+    }
+    CHECK(strstr(resp, "\"txid\":\"PEDIDO123\"") != NULL, "generate: missing txid in %s", resp);
+    CHECK(strstr(resp, "\"qrCode\":\"iVBOR") != NULL, "generate: expected a base64 PNG in %s", resp);
+    CHECK(extract_payload(resp, payload, sizeof payload), "generate: missing payload in %s", resp);
+    pixgen_free(resp);
+
+    parsed = pixgen_parse(payload, &err);
+    CHECK(parsed != NULL && err == NULL, "parse: %s", err ? err : "no result");
+    if (parsed != NULL) {
+        CHECK(strstr(parsed, "\"merchantName\":\"LOJA EXEMPLO\"") != NULL, "parse: unexpected %s", parsed);
+        pixgen_free(parsed);
+    }
+
+    /* a wrong CRC is reported through err */
+    payload[strlen(payload) - 1] = payload[strlen(payload) - 1] == '0' ? '1' : '0';
+    parsed = pixgen_parse(payload, &err);
+    CHECK(parsed == NULL && err != NULL, "parse: expected a CRC error");
+    pixgen_free(err);
+}
+
+static void test_validate(void) {
+    char *err = NULL;
+
+    CHECK(pixgen_validate((char *)request, &err) == 1 && err == NULL, "validate: %s", err);
+    CHECK(pixgen_validate("{\"pixKey\":\"+5511999999999\",\"merchantName\":\"Loja\"}", &err) == 0, "validate: expected missing city to fail");
+    CHECK(err != NULL && strstr(err, "merchantCity") != NULL, "validate: unexpected error %s", err ? err : "(null)");
+    pixgen_free(err);
+
+    CHECK(pixgen_validate("{not json", &err) == 0 && err != NULL, "validate: expected invalid json to fail");
+    pixgen_free(err);
+
+    /* err may be NULL when the caller does not want the message */
+    CHECK(pixgen_validate(NULL, NULL) == 0, "validate: expected a NULL request to fail");
+}
+
+static void test_png(void) {
+    static const unsigned char signature[8] = {0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'};
+    char *err = NULL;
+    size_t size = 0;
+    unsigned char *png = pixgen_png("{\"pixKey\":\"+5511999999999\",\"merchantName\":\"Loja\",\"merchantCity\":\"Sao Paulo\",\"size\":256,\"logo\":\"pix\"}", &size, &err);
+
+    CHECK(png != NULL && err == NULL, "png: %s", err ? err : "no image");
+    if (png != NULL) {
+        CHECK(size > sizeof signature && memcmp(png, signature, sizeof signature) == 0, "png: invalid image of %zu bytes", size);
+        pixgen_free(png);
+    }
+
+    png = pixgen_png("{\"merchantName\":\"Loja\",\"merchantCity\":\"Sao Paulo\"}", &size, &err);
+    CHECK(png == NULL && size == 0 && err != NULL, "png: expected a missing key to fail");
+    pixgen_free(err);
+}
+
+int main(void) {
+    test_generate_and_parse();
+    test_validate();
+    test_png();
+    if (failures > 0) {
+        fprintf(stderr, "%d check(s) failed\n", failures);
+        return 1;
+    }
+    printf("libpixgen: all checks passed\n");
+    return 0;
+}
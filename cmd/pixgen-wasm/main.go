@@ -0,0 +1,126 @@
+//go:build js && wasm
+// +build js,wasm
+
+// Command pixgen-wasm exposes the Pix generator to JavaScript. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o pixgen.wasm ./cmd/pixgen-wasm
+//
+// and load it with the wasm_exec.js shipped with the Go toolchain. Once go.run(instance)
+// is called, globalThis.pixgen holds generate, parse, validate and svg; each returns a
+// Promise. Requests are objects (or JSON strings) with the fields of the REST service body.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"syscall/js"
+
+	"github.com/thiagozs/go-pixgen/internal/bindings"
+	"github.com/thiagozs/go-pixgen/pix"
+)
+
+func main() {
+	api := js.Global().Get("Object").New()
+	api.Set("generate", promiseFunc(generate))
+	api.Set("parse", promiseFunc(parse))
+	api.Set("validate", promiseFunc(validate))
+	api.Set("svg", promiseFunc(svg))
+	js.Global().Set("pixgen", api)
+
+	// keep the exported functions alive
+	select {}
+}
+
+// generate(request) resolves to the REST response: payload, base64 qrCode, qrMetadata and
+// the parsed payload.
+func generate(args []js.Value) (interface{}, error) {
+	req, err := requestArg(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := bindings.Generate(req)
+	if err != nil {
+		return nil, err
+	}
+	return toJS(resp)
+}
+
+// parse(payload) resolves to the parsed payload; invalid payloads and CRCs reject.
+func parse(args []js.Value) (interface{}, error) {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return nil, errors.New("payload string is required")
+	}
+	parsed, err := pix.ParsePayload(args[0].String())
+	if err != nil {
+		return nil, err
+	}
+	return toJS(parsed)
+}
+
+// validate(request) resolves to {valid, error}; it only rejects malformed input.
+func validate(args []js.Value) (interface{}, error) {
+	req, err := requestArg(args)
+	if err != nil {
+		return nil, err
+	}
+	return toJS(bindings.Validate(req))
+}
+
+// svg(request) resolves to the SVG markup of the QR Code.
+func svg(args []js.Value) (interface{}, error) {
+	req, err := requestArg(args)
+	if err != nil {
+		return nil, err
+	}
+	data, err := bindings.SVG(req)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// promiseFunc wraps fn in a JavaScript function returning a Promise that resolves with its
+// result or rejects with an Error holding its message.
+func promiseFunc(fn func(args []js.Value) (interface{}, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		executor := js.FuncOf(func(this js.Value, p []js.Value) interface{} {
+			resolve, reject := p[0], p[1]
+			go func() {
+				result, err := fn(args)
+				if err != nil {
+					reject.Invoke(js.Global().Get("Error").New(err.Error()))
+					return
+				}
+				resolve.Invoke(result)
+			}()
+			return nil
+		})
+		// the Promise constructor calls the executor synchronously
+		defer executor.Release()
+		return js.Global().Get("Promise").New(executor)
+	})
+}
+
+// requestArg decodes the request from a plain object or a JSON string.
+func requestArg(args []js.Value) (bindings.Request, error) {
+	if len(args) == 0 {
+		return bindings.Request{}, errors.New("request is required")
+	}
+	switch arg := args[0]; arg.Type() {
+	case js.TypeString:
+		return bindings.DecodeRequest([]byte(arg.String()))
+	case js.TypeObject:
+		return bindings.DecodeRequest([]byte(js.Global().Get("JSON").Call("stringify", arg).String()))
+	default:
+		return bindings.Request{}, errors.New("request must be an object or a JSON string")
+	}
+}
+
+// toJS converts v to a JavaScript value through its JSON encoding.
+func toJS(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return js.Global().Get("JSON").Call("parse", string(data)), nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/thiagozs/go-pixgen/internal/bindings"
+	"github.com/thiagozs/go-pixgen/pix"
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+// cardRequest is the input of the card command and POST /pix/card. The card is built from
+// Payload when set (an existing copia-e-cola), otherwise from the Pix fields.
+type cardRequest struct {
+	pixRequest
+	Payload string `json:"payload,omitempty"`
+	Width   int    `json:"width,omitempty"`
+	Padding int    `json:"padding,omitempty"`
+}
+
+func newCardCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "card",
+		Short: "Render a payment card image (QR code, merchant, amount and copia-e-cola)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+
+			pixReq, profiles, err := collectPixRequest(cmd)
+			if err != nil {
+				return err
+			}
+			req := cardRequest{
+				pixRequest: pixReq,
+				Payload:    flags.Lookup("payload").Value.String(),
+			}
+			for name, dst := range map[string]*int{"width": &req.Width, "padding": &req.Padding} {
+				v, err := strconv.Atoi(flags.Lookup(name).Value.String())
+				if err != nil {
+					return fmt.Errorf("invalid %s: %w", name, err)
+				}
+				*dst = v
+			}
+			if v := flags.Lookup("expires-at").Value.String(); v != "" {
+				expires, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					return fmt.Errorf("invalid expires-at (expected RFC 3339): %w", err)
+				}
+				req.ExpiresAt = &expires
+			}
+
+			card, format, err := renderCard(req, profiles)
+			if err != nil {
+				return err
+			}
+
+			if out := flags.Lookup("out").Value.String(); out != "" {
+				if err := os.WriteFile(out, card, 0o644); err != nil {
+					return fmt.Errorf("write card: %w", err)
+				}
+				fmt.Printf("Card (%s) written to %s\n", format, out)
+			} else if format == bindings.FormatSVG {
+				fmt.Print(string(card))
+			} else {
+				fmt.Printf("Card (base64): %s\n", base64.StdEncoding.EncodeToString(card))
+			}
+			return nil
+		},
+	}
+
+	addPixFlags(cmd)
+	flags := cmd.Flags()
+	flags.String("payload", "", "Existing Pix copia-e-cola to render instead of building one from flags")
+	flags.String("format", bindings.FormatPNG, "Card image format: png or svg")
+	flags.String("out", "", "Write the card to this file instead of printing it")
+	flags.Int("width", pix.DefaultCardWidth, "Card width in pixels")
+	flags.Int("padding", pix.DefaultCardPadding, "Card padding in pixels")
+	flags.String("expires-at", "", "Expiry shown on the card (RFC 3339, optional)")
+	flags.String("ecc", "M", "QR code error correction level: L, M, Q or H")
+	flags.String("logo", "", "Center logo: \"pix\" for the built-in Pix logo or a PNG/JPEG/SVG file (forces ECC H)")
+	flags.Float64("logo-coverage", qrcode.DefaultLogoCoverage, "Fraction of the QR code area reserved for the logo")
+
+	return cmd
+}
+
+// renderCard builds the payment card for req and returns it with the resolved format.
+func renderCard(req cardRequest, profiles pix.Profiles) ([]byte, string, error) {
+	format, err := bindings.ParseFormat(req.Format)
+	if err != nil {
+		return nil, "", err
+	}
+	if format != bindings.FormatPNG && format != bindings.FormatSVG {
+		return nil, "", errors.New("invalid card format (expected png or svg)")
+	}
+	style, err := bindings.ParseStyle(req.Request)
+	if err != nil {
+		return nil, "", err
+	}
+
+	parsed, err := resolvePayload(req.pixRequest, req.Payload, profiles)
+	if err != nil {
+		return nil, "", err
+	}
+
+	opts := pix.CardOptions{
+		Width:     req.Width,
+		Padding:   req.Padding,
+		ExpiresAt: req.ExpiresAt,
+		Level:     style.Level,
+		Logo:      style.Logo,
+	}
+	var card []byte
+	if format == bindings.FormatSVG {
+		card, err = pix.NewCardSVG(parsed, opts)
+	} else {
+		card, err = pix.NewCardPNG(parsed, opts)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return card, format, nil
+}
+
+// resolvePayload parses payload, an existing copia-e-cola, or when it is empty the payload
+// built from the Pix fields of req.
+func resolvePayload(req pixRequest, payload string, profiles pix.Profiles) (*pix.ParsedPayload, error) {
+	payload = strings.TrimSpace(payload)
+	if payload == "" {
+		params, err := requestToParams(req, profiles)
+		if err != nil {
+			return nil, err
+		}
+		p, err := newPix(params)
+		if err != nil {
+			return nil, err
+		}
+		if payload, err = p.GenPayload(); err != nil {
+			return nil, err
+		}
+	}
+	return pix.ParsePayload(payload)
+}
+
+func newCardHandler(profiles pix.Profiles) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req cardRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid json: %v", err), http.StatusBadRequest)
+			return
+		}
+		log.Printf("pix card request received: profile=%s merchant=%s amount=%s format=%s",
+			req.Profile, req.MerchantName, req.Amount, req.Format)
+
+		card, format, err := renderCard(req, profiles)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		contentType := "image/png"
+		if format == bindings.FormatSVG {
+			contentType = "image/svg+xml"
+		}
+		w.Header().Set("Content-Type", contentType)
+		if _, err := w.Write(card); err != nil {
+			log.Printf("write card response: %v", err)
+		}
+	}
+}
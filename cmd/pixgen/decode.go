@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/thiagozs/go-pixgen/pix"
+)
+
+func newDecodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decode",
+		Short: "Read Pix payloads from QR codes in PNG/JPEG images or PDFs (decode [flags] image.png ...)",
+		Long: `Read Pix payloads from QR codes in PNG/JPEG images or PDFs (decode [flags] image.png ...).
+
+Every QR code in each file is decoded and validated; - reads the file from stdin.
+PDF pages drawn with vector modules (generate --format pdf, sheet) are rasterized, and
+JPEG or 8-bit gray/RGB Flate images embedded in scanned PDFs are decoded directly.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("decode requires at least one image file (- reads stdin)")
+			}
+			asJSON := cmd.Flags().Lookup("json").Value.String() == "true"
+
+			var all []*pix.ParsedPayload
+			for _, path := range args {
+				payloads, err := decodeImageFile(path)
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				if asJSON {
+					all = append(all, payloads...)
+					continue
+				}
+				for _, parsed := range payloads {
+					fmt.Printf("File: %s\n", path)
+					fmt.Printf("Copy and Paste: %s\n", parsed.Raw)
+					fmt.Printf("Kind: %s\n", parsed.Kind())
+					fmt.Printf("Merchant: %s (%s)\n", parsed.MerchantName, parsed.MerchantCity)
+					if parsed.TransactionAmount != "" {
+						fmt.Printf("Amount: %s\n", parsed.TransactionAmount)
+					}
+					fmt.Printf("TxID: %s\n", parsed.AdditionalDataField.TxID)
+				}
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(all)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("json", false, "Print the parsed payloads as a JSON array")
+	return cmd
+}
+
+func decodeImageFile(path string) ([]*pix.ParsedPayload, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return pix.ParseImage(r)
+}
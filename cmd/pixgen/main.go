@@ -14,7 +14,9 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/thiagozs/go-pixgen/internal/bindings"
 	"github.com/thiagozs/go-pixgen/pix"
+	"github.com/thiagozs/go-pixgen/qrcode"
 )
 
 func main() {
@@ -35,7 +37,7 @@ func newRootCmd() *cobra.Command {
 		},
 	}
 
-	cmd.AddCommand(newGenerateCmd(), newServeCmd())
+	cmd.AddCommand(newGenerateCmd(), newCardCmd(), newDecodeCmd(), newPrintCmd(), newSheetCmd(), newServeCmd())
 	return cmd
 }
 
@@ -49,27 +51,83 @@ func newGenerateCmd() *cobra.Command {
 				return err
 			}
 
-			payload, qr, asciiQR, parsed, err := buildPix(params)
+			result, err := buildPix(params)
 			if err != nil {
 				return err
 			}
+			qr := result.QRCode
 
-			fmt.Printf("Copy and Paste: %s\n", payload)
-			if parsed != nil {
+			fmt.Printf("Copy and Paste: %s\n", result.Payload)
+			if parsed := result.Parsed; parsed != nil {
 				fmt.Printf("Kind: %s\n", parsed.Kind())
 				fmt.Printf("Merchant: %s (%s)\n", parsed.MerchantName, parsed.MerchantCity)
 				fmt.Printf("TxID: %s\n", parsed.AdditionalDataField.TxID)
 			}
-			fmt.Printf("QR Code (base64): %s\n", base64.StdEncoding.EncodeToString(qr))
-			if asciiQR != "" {
+			meta := result.Metadata
+			fmt.Printf("QR Version: %d (%dx%d modules, ECC %s, %d/%d bytes, headroom %d)\n",
+				meta.Version, meta.Modules, meta.Modules, meta.Level, meta.Length, meta.Capacity, meta.Headroom)
+			if out := cmd.Flags().Lookup("out").Value.String(); out != "" {
+				if err := os.WriteFile(out, qr, 0o644); err != nil {
+					return fmt.Errorf("write qr code: %w", err)
+				}
+				fmt.Printf("QR Code (%s) written to %s\n", params.Format, out)
+			} else if params.Format == bindings.FormatSVG {
+				fmt.Println("QR Code (SVG):")
+				fmt.Print(string(qr))
+			} else if params.Format == bindings.FormatZPL {
+				fmt.Println("Label (ZPL):")
+				fmt.Print(string(qr))
+			} else if params.Format == bindings.FormatHTML {
+				fmt.Println("Checkout page (HTML):")
+				fmt.Print(string(qr))
+			} else {
+				fmt.Printf("QR Code (base64): %s\n", base64.StdEncoding.EncodeToString(qr))
+			}
+			if params.ASCII.Image != "" {
+				fmt.Printf("QR Code (%s):\n", params.ASCII.Image)
+				fmt.Println(result.ASCII)
+			} else if result.ASCII != "" {
 				fmt.Println("QR Code (ASCII):")
-				fmt.Println(asciiQR)
+				fmt.Println(result.ASCII)
 			}
 
 			return nil
 		},
 	}
 
+	addPixFlags(cmd)
+	flags := cmd.Flags()
+	flags.String("format", bindings.FormatPNG, "QR code output format: png, svg, pdf, zpl (Zebra label) or html (checkout page)")
+	flags.String("out", "", "Write the QR code image to this file instead of printing it")
+	flags.String("ecc", "M", "QR code error correction level: L, M, Q or H")
+	flags.String("qr-fg", "", "QR code foreground color (#RRGGBB, default black)")
+	flags.String("qr-bg", "", "QR code background color (#RRGGBB, default white)")
+	flags.Bool("qr-transparent", false, "Render the PNG QR code background as transparent")
+	flags.Int("qr-quiet-zone", qrcode.DefaultQuietZone, "QR code quiet zone width in modules")
+	flags.Bool("qr-no-border", false, "Disable the QR code quiet zone border")
+	flags.String("logo", "", "Center logo: \"pix\" for the built-in Pix logo or a PNG/JPEG/SVG file (forces ECC H)")
+	flags.Float64("logo-coverage", qrcode.DefaultLogoCoverage, "Fraction of the QR code area reserved for the logo")
+	flags.Float64("pdf-size", 0, "PDF QR code side in millimeters (default 50 when omitted)")
+	flags.Float64("pdf-bleed", 0, "PDF bleed in millimeters around the QR code")
+	flags.Bool("pdf-crop-marks", false, "Draw crop marks in PDF output")
+	flags.Float64("zpl-width", pix.DefaultZPLLabelWidth, "ZPL label width in millimeters")
+	flags.Float64("zpl-height", pix.DefaultZPLLabelHeight, "ZPL label height in millimeters")
+	flags.Int("zpl-dpi", pix.DefaultZPLDPI, "ZPL printer resolution: 152, 203, 300 or 600 dpi")
+	flags.Int("qr-size", 0, "PNG QR code size in pixels (default 256 when omitted)")
+	flags.Int("ascii-scale", 1, "Scale factor (>=1) for ASCII QR output")
+	flags.Bool("ascii-quiet", false, "Include quiet zone border in ASCII QR output")
+	flags.String("ascii-black", "", "Character(s) used for dark modules in ASCII QR output")
+	flags.String("ascii-white", "", "Character(s) used for light modules in ASCII QR output")
+	flags.String("ascii-mode", asciiModeFull, "ASCII QR style: full (two characters per module) or halfblock (Unicode half blocks)")
+	flags.Bool("ascii-inverted", false, "Half-block output for light terminals (draws dark modules)")
+	flags.Int("terminal-width", 0, "Columns available for half-block output (default $COLUMNS or 80)")
+	flags.String("terminal-image", "", "Show the QR code as a terminal image instead of text: sixel or kitty")
+
+	return cmd
+}
+
+// addPixFlags registers the payload flags shared by the commands that build a Pix.
+func addPixFlags(cmd *cobra.Command) {
 	flags := cmd.Flags()
 	flags.String("kind", "static", "Pix kind: static or dynamic")
 	flags.String("key", "", "Pix key (required for static)")
@@ -80,13 +138,8 @@ func newGenerateCmd() *cobra.Command {
 	flags.String("description", "", "Transaction description (optional)")
 	flags.String("additional-info", "", "Additional info (static only)")
 	flags.String("txid", "", "Transaction identifier (optional)")
-	flags.Int("qr-size", 0, "PNG QR code size in pixels (default 256 when omitted)")
-	flags.Int("ascii-scale", 1, "Scale factor (>=1) for ASCII QR output")
-	flags.Bool("ascii-quiet", false, "Include quiet zone border in ASCII QR output")
-	flags.String("ascii-black", "", "Character(s) used for dark modules in ASCII QR output")
-	flags.String("ascii-white", "", "Character(s) used for light modules in ASCII QR output")
-
-	return cmd
+	flags.String("profile", "", "Merchant profile name to load defaults from")
+	flags.String("profiles", "", "Profiles file (JSON or YAML); defaults to $PIXGEN_PROFILES")
 }
 
 func newServeCmd() *cobra.Command {
@@ -97,12 +150,47 @@ func newServeCmd() *cobra.Command {
 			flags := cmd.Flags()
 			addr := flags.Lookup("addr").Value.String()
 
+			var profiles pix.Profiles
+			if path := flags.Lookup("profiles").Value.String(); path != "" || os.Getenv("PIXGEN_PROFILES") != "" {
+				var err error
+				profiles, err = loadProfilesFile(path)
+				if err != nil {
+					return err
+				}
+				log.Printf("loaded %d merchant profiles", len(profiles))
+			}
+
+			cacheSize, err := strconv.Atoi(flags.Lookup("cache-size").Value.String())
+			if err != nil || cacheSize < 0 {
+				return fmt.Errorf("invalid cache-size: %q", flags.Lookup("cache-size").Value.String())
+			}
+			var cache *qrcode.Cache
+			if cacheSize > 0 {
+				if cache, err = qrcode.NewCache(cacheSize); err != nil {
+					return err
+				}
+				log.Printf("png render cache enabled: %d entries", cacheSize)
+			}
+
+			pageStoreSize, err := strconv.Atoi(flags.Lookup("page-store-size").Value.String())
+			if err != nil || pageStoreSize < 0 {
+				return fmt.Errorf("invalid page-store-size: %q", flags.Lookup("page-store-size").Value.String())
+			}
+			var pages *pageStore
+			if pageStoreSize > 0 {
+				pages = newPageStore(pageStoreSize)
+			}
+
 			handler := http.NewServeMux()
 			handler.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
 				w.WriteHeader(http.StatusOK)
 				_, _ = w.Write([]byte("ok"))
 			})
-			handler.HandleFunc("/pix", pixHandler)
+			handler.HandleFunc("/pix", newPixHandler(profiles, cache, pages))
+			handler.HandleFunc("/pix/card", newCardHandler(profiles))
+			if pages != nil {
+				handler.HandleFunc("/pix/", newPageHandler(pages))
+			}
 
 			server := &http.Server{
 				Addr:         addr,
@@ -118,30 +206,43 @@ func newServeCmd() *cobra.Command {
 	}
 
 	cmd.Flags().String("addr", ":8080", "HTTP listen address")
+	cmd.Flags().String("profiles", "", "Profiles file (JSON or YAML); defaults to $PIXGEN_PROFILES")
+	cmd.Flags().Int("cache-size", qrcode.DefaultCacheCapacity, "PNG QR codes kept in the LRU render cache (0 disables it)")
+	cmd.Flags().Int("page-store-size", 0, "Charges kept in memory for GET /pix/{id}/page (0, the default, disables checkout pages)")
 
 	return cmd
 }
 
+// pixRequest is the body of POST /pix: the bindings request plus the name of a profile
+// filling the fields left empty and the expiry shown on the checkout page and card.
 type pixRequest struct {
-	Kind           string `json:"kind"`
-	PixKey         string `json:"pixKey"`
-	URL            string `json:"url"`
-	MerchantName   string `json:"merchantName"`
-	MerchantCity   string `json:"merchantCity"`
-	Amount         string `json:"amount"`
-	Description    string `json:"description"`
-	AdditionalInfo string `json:"additionalInfo"`
-	TxID           string `json:"txid"`
+	bindings.Request
+	Profile   string     `json:"profile,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
 type pixResponse struct {
-	Payload string `json:"payload"`
-	QRCode  string `json:"qrCode"`
-	Kind    string `json:"kind"`
-	TxID    string `json:"txid"`
+	Payload    string             `json:"payload"`
+	QRCode     string             `json:"qrCode"`
+	Format     string             `json:"format"`
+	QRMetadata qrcode.Metadata    `json:"qrMetadata"`
+	Kind       string             `json:"kind"`
+	TxID       string             `json:"txid"`
+	Parsed     *pix.ParsedPayload `json:"parsed"`
+	// ID and PageURL identify the checkout page of the charge (GET /pix/{id}/page).
+	ID      string `json:"id,omitempty"`
+	PageURL string `json:"pageUrl,omitempty"`
 }
 
-func pixHandler(w http.ResponseWriter, r *http.Request) {
+// newPixHandler serves POST /pix; PNG renders go through cache and charges are kept in
+// pages for their checkout page when they are not nil.
+func newPixHandler(profiles pix.Profiles, cache *qrcode.Cache, pages *pageStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pixHandler(w, r, profiles, cache, pages)
+	}
+}
+
+func pixHandler(w http.ResponseWriter, r *http.Request, profiles pix.Profiles, cache *qrcode.Cache, pages *pageStore) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -152,26 +253,39 @@ func pixHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("invalid json: %v", err), http.StatusBadRequest)
 		return
 	}
-	log.Printf("pix request received: kind=%s merchant=%s city=%s amount=%s txid=%s",
-		req.Kind, req.MerchantName, req.MerchantCity, req.Amount, req.TxID)
+	log.Printf("pix request received: kind=%s profile=%s merchant=%s city=%s amount=%s txid=%s",
+		req.Kind, req.Profile, req.MerchantName, req.MerchantCity, req.Amount, req.TxID)
 
-	params, err := requestToParams(req)
+	params, err := requestToParams(req, profiles)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	params.Cache = cache
 
-	payload, qr, _, parsed, err := buildPix(params)
+	result, err := buildPix(params)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	resp := pixResponse{
-		Payload: payload,
-		QRCode:  base64.StdEncoding.EncodeToString(qr),
-		Kind:    parsed.Kind().String(),
-		TxID:    parsed.AdditionalDataField.TxID,
+		Payload:    result.Payload,
+		QRCode:     base64.StdEncoding.EncodeToString(result.QRCode),
+		Format:     params.Format,
+		QRMetadata: result.Metadata,
+		Kind:       result.Parsed.Kind().String(),
+		TxID:       result.Parsed.AdditionalDataField.TxID,
+		Parsed:     result.Parsed,
+	}
+	if pages != nil {
+		id, err := pages.add(params, result.Parsed, req.ExpiresAt)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("store charge: %v", err), http.StatusInternalServerError)
+			return
+		}
+		resp.ID = id
+		resp.PageURL = "/pix/" + id + "/page"
 	}
 	log.Printf("pix response sent: kind=%s txid=%s payload_len=%d qr_len=%d",
 		resp.Kind, resp.TxID, len(resp.Payload), len(resp.QRCode))
@@ -182,44 +296,165 @@ func pixHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// pixParams is a request with its profile applied and its format and style parsed, plus
+// the settings only the CLI takes from flags.
 type pixParams struct {
-	Kind           pix.PixKind
-	PixKey         string
-	URL            string
-	MerchantName   string
-	MerchantCity   string
-	Amount         string
-	Description    string
-	AdditionalInfo string
-	TxID           string
-	QRCodeSize     int
-	ASCII          asciiParams
+	Request bindings.Request
+	Format  string
+	Style   bindings.Style
+	PDF     pdfParams
+	ZPL     zplParams
+	ASCII   asciiParams
+	// Cache, when set, is shared by the PNG renders (serve).
+	Cache *qrcode.Cache
+}
+
+type zplParams struct {
+	WidthMM  float64
+	HeightMM float64
+	DPI      int
+}
+
+type pdfParams struct {
+	SizeMM    float64
+	BleedMM   float64
+	CropMarks bool
 }
 
 type asciiParams struct {
+	// Enabled renders the terminal output (generate); serve leaves it off.
+	Enabled   bool
 	Scale     int
 	Quiet     bool
 	QuietSet  bool
 	BlackChar string
 	WhiteChar string
+	HalfBlock bool
+	Inverted  bool
+	Width     int
+	// Image selects a terminal graphics protocol (sixel or kitty) instead of text.
+	Image string
+}
+
+const (
+	asciiModeFull      = "full"
+	asciiModeHalfBlock = "halfblock"
+
+	terminalImageSixel = "sixel"
+	terminalImageKitty = "kitty"
+)
+
+// terminalWidth returns the width to fit half-block output into: the flag value, then
+// $COLUMNS, then the classic 80 columns.
+func terminalWidth(flagValue int) int {
+	if flagValue > 0 {
+		return flagValue
+	}
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	return 80
+}
+
+// collectPixRequest builds the request from the command flags. Only the payload flags are
+// required; rendering flags are read when the command registers them.
+func collectPixRequest(cmd *cobra.Command) (pixRequest, pix.Profiles, error) {
+	flags := cmd.Flags()
+
+	req := pixRequest{
+		Request: bindings.Request{
+			PixKey:         flags.Lookup("key").Value.String(),
+			URL:            flags.Lookup("url").Value.String(),
+			MerchantName:   flags.Lookup("merchant-name").Value.String(),
+			MerchantCity:   flags.Lookup("merchant-city").Value.String(),
+			Amount:         flags.Lookup("amount").Value.String(),
+			Description:    flags.Lookup("description").Value.String(),
+			AdditionalInfo: flags.Lookup("additional-info").Value.String(),
+			TxID:           flags.Lookup("txid").Value.String(),
+		},
+		Profile: flags.Lookup("profile").Value.String(),
+	}
+
+	for name, dst := range map[string]*string{"format": &req.Format, "ecc": &req.ECC, "qr-fg": &req.Foreground, "qr-bg": &req.Background} {
+		if fl := flags.Lookup(name); fl != nil {
+			*dst = fl.Value.String()
+		}
+	}
+
+	if fl := flags.Lookup("logo"); fl != nil && fl.Value.String() != "" {
+		logo := fl.Value.String()
+		if strings.EqualFold(logo, bindings.BuiltinLogo) {
+			req.Logo = bindings.BuiltinLogo
+		} else {
+			data, err := os.ReadFile(logo)
+			if err != nil {
+				return pixRequest{}, nil, fmt.Errorf("read logo: %w", err)
+			}
+			req.Logo = base64.StdEncoding.EncodeToString(data)
+		}
+		coverage, err := strconv.ParseFloat(flags.Lookup("logo-coverage").Value.String(), 64)
+		if err != nil {
+			return pixRequest{}, nil, fmt.Errorf("invalid logo-coverage: %w", err)
+		}
+		req.LogoCoverage = coverage
+	}
+
+	if fl := flags.Lookup("qr-transparent"); fl != nil && fl.Value.String() != fl.DefValue {
+		transparent, err := strconv.ParseBool(fl.Value.String())
+		if err != nil {
+			return pixRequest{}, nil, fmt.Errorf("invalid qr-transparent: %w", err)
+		}
+		req.Transparent = transparent
+	}
+
+	if fl := flags.Lookup("qr-quiet-zone"); fl != nil && fl.Value.String() != fl.DefValue {
+		quiet, err := strconv.Atoi(fl.Value.String())
+		if err != nil {
+			return pixRequest{}, nil, fmt.Errorf("invalid qr-quiet-zone: %w", err)
+		}
+		req.QuietZone = &quiet
+	}
+
+	if fl := flags.Lookup("qr-no-border"); fl != nil && fl.Value.String() != fl.DefValue {
+		noBorder, err := strconv.ParseBool(fl.Value.String())
+		if err != nil {
+			return pixRequest{}, nil, fmt.Errorf("invalid qr-no-border: %w", err)
+		}
+		if noBorder {
+			zero := 0
+			req.QuietZone = &zero
+		}
+	}
+	// an explicit --kind wins over the profile; otherwise the profile (or static) applies
+	if fl := flags.Lookup("kind"); fl.Value.String() != fl.DefValue || req.Profile == "" {
+		req.Kind = fl.Value.String()
+	}
+
+	var profiles pix.Profiles
+	if req.Profile != "" {
+		var err error
+		profiles, err = loadProfilesFile(flags.Lookup("profiles").Value.String())
+		if err != nil {
+			return pixRequest{}, nil, err
+		}
+	}
+
+	return req, profiles, nil
 }
 
 func collectPixParams(cmd *cobra.Command) (pixParams, error) {
 	flags := cmd.Flags()
-	params, err := parseParams(
-		flags.Lookup("kind").Value.String(),
-		flags.Lookup("key").Value.String(),
-		flags.Lookup("url").Value.String(),
-		flags.Lookup("merchant-name").Value.String(),
-		flags.Lookup("merchant-city").Value.String(),
-		flags.Lookup("amount").Value.String(),
-		flags.Lookup("description").Value.String(),
-		flags.Lookup("additional-info").Value.String(),
-		flags.Lookup("txid").Value.String(),
-	)
+
+	req, profiles, err := collectPixRequest(cmd)
+	if err != nil {
+		return pixParams{}, err
+	}
+
+	params, err := requestToParams(req, profiles)
 	if err != nil {
 		return pixParams{}, err
 	}
+	params.ASCII.Enabled = true
 
 	if fl := flags.Lookup("qr-size"); fl != nil && fl.Value.String() != fl.DefValue {
 		size, err := strconv.Atoi(fl.Value.String())
@@ -229,7 +464,45 @@ func collectPixParams(cmd *cobra.Command) (pixParams, error) {
 		if size <= 0 {
 			return pixParams{}, fmt.Errorf("qr-size must be greater than zero")
 		}
-		params.QRCodeSize = size
+		params.Request.Size = size
+	}
+
+	for name, dst := range map[string]*float64{"pdf-size": &params.PDF.SizeMM, "pdf-bleed": &params.PDF.BleedMM} {
+		if fl := flags.Lookup(name); fl != nil && fl.Value.String() != fl.DefValue {
+			v, err := strconv.ParseFloat(fl.Value.String(), 64)
+			if err != nil {
+				return pixParams{}, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			if v < 0 {
+				return pixParams{}, fmt.Errorf("%s must not be negative", name)
+			}
+			*dst = v
+		}
+	}
+
+	for name, dst := range map[string]*float64{"zpl-width": &params.ZPL.WidthMM, "zpl-height": &params.ZPL.HeightMM} {
+		if fl := flags.Lookup(name); fl != nil {
+			v, err := strconv.ParseFloat(fl.Value.String(), 64)
+			if err != nil {
+				return pixParams{}, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			*dst = v
+		}
+	}
+	if fl := flags.Lookup("zpl-dpi"); fl != nil {
+		dpi, err := strconv.Atoi(fl.Value.String())
+		if err != nil {
+			return pixParams{}, fmt.Errorf("invalid zpl-dpi: %w", err)
+		}
+		params.ZPL.DPI = dpi
+	}
+
+	if fl := flags.Lookup("pdf-crop-marks"); fl != nil && fl.Value.String() != fl.DefValue {
+		marks, err := strconv.ParseBool(fl.Value.String())
+		if err != nil {
+			return pixParams{}, fmt.Errorf("invalid pdf-crop-marks: %w", err)
+		}
+		params.PDF.CropMarks = marks
 	}
 
 	if fl := flags.Lookup("ascii-scale"); fl != nil && fl.Value.String() != fl.DefValue {
@@ -260,97 +533,180 @@ func collectPixParams(cmd *cobra.Command) (pixParams, error) {
 		params.ASCII.WhiteChar = fl.Value.String()
 	}
 
-	return params, nil
-}
+	if fl := flags.Lookup("ascii-mode"); fl != nil {
+		switch mode := strings.ToLower(strings.TrimSpace(fl.Value.String())); mode {
+		case "", asciiModeFull:
+		case asciiModeHalfBlock:
+			params.ASCII.HalfBlock = true
+		default:
+			return pixParams{}, fmt.Errorf("invalid ascii-mode %q (expected full or halfblock)", mode)
+		}
+	}
 
-func requestToParams(req pixRequest) (pixParams, error) {
-	return parseParams(
-		req.Kind,
-		req.PixKey,
-		req.URL,
-		req.MerchantName,
-		req.MerchantCity,
-		req.Amount,
-		req.Description,
-		req.AdditionalInfo,
-		req.TxID,
-	)
+	if fl := flags.Lookup("ascii-inverted"); fl != nil && fl.Value.String() != fl.DefValue {
+		inverted, err := strconv.ParseBool(fl.Value.String())
+		if err != nil {
+			return pixParams{}, fmt.Errorf("invalid ascii-inverted: %w", err)
+		}
+		params.ASCII.Inverted = inverted
+	}
+
+	if fl := flags.Lookup("terminal-image"); fl != nil {
+		switch image := strings.ToLower(strings.TrimSpace(fl.Value.String())); image {
+		case "":
+		case terminalImageSixel, terminalImageKitty:
+			params.ASCII.Image = image
+		default:
+			return pixParams{}, fmt.Errorf("invalid terminal-image %q (expected sixel or kitty)", image)
+		}
+	}
+
+	if params.ASCII.HalfBlock {
+		width, err := strconv.Atoi(flags.Lookup("terminal-width").Value.String())
+		if err != nil || width < 0 {
+			return pixParams{}, fmt.Errorf("invalid terminal-width %q", flags.Lookup("terminal-width").Value.String())
+		}
+		params.ASCII.Width = terminalWidth(width)
+	}
+
+	return params, nil
 }
 
-func parseParams(
-	kindStr, key, url, merchantName, merchantCity, amount, description, additional, txid string,
-) (pixParams, error) {
-	kind, err := parseKind(kindStr)
+func requestToParams(req pixRequest, profiles pix.Profiles) (pixParams, error) {
+	req, err := applyProfile(req, profiles)
 	if err != nil {
 		return pixParams{}, err
 	}
 
-	if merchantName == "" {
-		return pixParams{}, errors.New("merchant-name is required")
+	params := pixParams{Request: req.Request}
+	params.Format, err = bindings.ParseFormat(req.Format)
+	if err != nil {
+		return pixParams{}, err
 	}
-	if merchantCity == "" {
-		return pixParams{}, errors.New("merchant-city is required")
+	params.Style, err = bindings.ParseStyle(req.Request)
+	if err != nil {
+		return pixParams{}, err
+	}
+
+	return params, nil
+}
+
+// applyProfile fills the request fields left empty with the values of the named profile.
+func applyProfile(req pixRequest, profiles pix.Profiles) (pixRequest, error) {
+	if strings.TrimSpace(req.Profile) == "" {
+		return req, nil
 	}
 
-	if kind == pix.STATIC && key == "" {
-		return pixParams{}, errors.New("pix key is required for static payloads")
+	profile, ok := profiles.Get(req.Profile)
+	if !ok {
+		return req, fmt.Errorf("profile %q not found", req.Profile)
 	}
-	if kind == pix.DYNAMIC && url == "" {
-		return pixParams{}, errors.New("url is required for dynamic payloads")
+
+	fill := func(dst *string, value string) {
+		if *dst == "" {
+			*dst = value
+		}
 	}
+	fill(&req.Kind, profile.Kind)
+	fill(&req.PixKey, profile.PixKey)
+	fill(&req.URL, profile.URL)
+	fill(&req.MerchantName, profile.MerchantName)
+	fill(&req.MerchantCity, profile.MerchantCity)
+	fill(&req.Description, profile.Description)
+	fill(&req.AdditionalInfo, profile.AdditionalInfo)
 
-	return pixParams{
-		Kind:           kind,
-		PixKey:         key,
-		URL:            url,
-		MerchantName:   merchantName,
-		MerchantCity:   merchantCity,
-		Amount:         amount,
-		Description:    description,
-		AdditionalInfo: additional,
-		TxID:           txid,
-	}, nil
+	return req, nil
 }
 
-func parseKind(kind string) (pix.PixKind, error) {
-	switch strings.ToLower(strings.TrimSpace(kind)) {
-	case "", "static":
-		return pix.STATIC, nil
-	case "dynamic":
-		return pix.DYNAMIC, nil
-	default:
-		return pix.STATIC, fmt.Errorf("invalid kind %q (expected static or dynamic)", kind)
+func loadProfilesFile(path string) (pix.Profiles, error) {
+	if path == "" {
+		path = os.Getenv("PIXGEN_PROFILES")
+	}
+	if path == "" {
+		return nil, errors.New("profiles file is required (use --profiles or PIXGEN_PROFILES)")
 	}
-}
 
-func buildPix(params pixParams) (string, []byte, string, *pix.ParsedPayload, error) {
-	opts := []pix.Options{
-		pix.OptKind(params.Kind),
-		pix.OptMerchantName(params.MerchantName),
-		pix.OptMerchantCity(params.MerchantCity),
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open profiles: %w", err)
 	}
+	defer f.Close()
+
+	return pix.LoadProfiles(f)
+}
 
-	if params.PixKey != "" {
-		opts = append(opts, pix.OptPixKey(params.PixKey))
+type pixOutput struct {
+	Payload  string
+	QRCode   []byte
+	ASCII    string
+	Parsed   *pix.ParsedPayload
+	Metadata qrcode.Metadata
+}
+
+func buildPix(params pixParams) (pixOutput, error) {
+	p, err := newPix(params)
+	if err != nil {
+		return pixOutput{}, err
 	}
-	if params.Amount != "" {
-		opts = append(opts, pix.OptAmount(params.Amount))
+
+	payload, err := p.GenPayload()
+	if err != nil {
+		return pixOutput{}, err
 	}
-	if params.Description != "" {
-		opts = append(opts, pix.OptDescription(params.Description))
+
+	parsed, err := pix.ParsePayload(payload)
+	if err != nil {
+		return pixOutput{}, err
 	}
-	if params.AdditionalInfo != "" {
-		opts = append(opts, pix.OptAdditionalInfo(params.AdditionalInfo))
+
+	qr, err := bindings.Render(p, parsed, params.Format, params.Style, bindings.RenderOptions{
+		PDF: qrcode.PDFOptions{
+			SizeMM:    params.PDF.SizeMM,
+			BleedMM:   params.PDF.BleedMM,
+			CropMarks: params.PDF.CropMarks,
+		},
+		ZPL: pix.ZPLOptions{
+			WidthMM:  params.ZPL.WidthMM,
+			HeightMM: params.ZPL.HeightMM,
+			DPI:      params.ZPL.DPI,
+		},
+	})
+	if err != nil {
+		return pixOutput{}, err
 	}
-	if params.TxID != "" {
-		opts = append(opts, pix.OptTxId(params.TxID))
+
+	var asciiQR string
+	switch {
+	case !params.ASCII.Enabled:
+	case params.ASCII.Image == terminalImageSixel:
+		asciiQR, err = p.GenQRCodeSixel()
+	case params.ASCII.Image == terminalImageKitty:
+		asciiQR, err = p.GenQRCodeKitty()
+	default:
+		asciiQR, err = p.GenQRCodeASCII()
 	}
-	if params.URL != "" {
-		opts = append(opts, pix.OptUrl(params.URL))
+	if err != nil {
+		return pixOutput{}, err
 	}
-	if params.QRCodeSize > 0 {
-		opts = append(opts, pix.OptQRCodeSize(params.QRCodeSize))
+
+	meta, err := p.QRCodeMetadata()
+	if err != nil {
+		return pixOutput{}, err
 	}
+
+	return pixOutput{
+		Payload:  payload,
+		QRCode:   qr,
+		ASCII:    asciiQR,
+		Parsed:   parsed,
+		Metadata: meta,
+	}, nil
+}
+
+// newPix creates the Pix of params through bindings.New, adding the options the request
+// does not carry: ASCII output and the render cache.
+func newPix(params pixParams) (*pix.Pix, error) {
+	var opts []pix.Options
 	if params.ASCII.Scale > 0 {
 		opts = append(opts, pix.OptQRCodeScale(params.ASCII.Scale))
 	}
@@ -360,30 +716,12 @@ func buildPix(params pixParams) (string, []byte, string, *pix.ParsedPayload, err
 	if params.ASCII.QuietSet {
 		opts = append(opts, pix.OptASCIIQuietZone(params.ASCII.Quiet))
 	}
-
-	p, err := pix.New(opts...)
-	if err != nil {
-		return "", nil, "", nil, err
+	if params.ASCII.HalfBlock {
+		opts = append(opts, pix.OptASCIIHalfBlock(params.ASCII.Inverted), pix.OptASCIITerminalWidth(params.ASCII.Width))
 	}
-
-	payload, err := p.GenPayload()
-	if err != nil {
-		return "", nil, "", nil, err
-	}
-	qr, err := p.GenQRCode()
-	if err != nil {
-		return "", nil, "", nil, err
-	}
-
-	asciiQR, err := p.GenQRCodeASCII()
-	if err != nil {
-		return "", nil, "", nil, err
-	}
-
-	parsed, err := pix.ParsePayload(payload)
-	if err != nil {
-		return "", nil, "", nil, err
+	if params.Cache != nil {
+		opts = append(opts, pix.OptQRCodeCache(params.Cache))
 	}
 
-	return payload, qr, asciiQR, parsed, nil
+	return bindings.New(params.Request, opts...)
 }
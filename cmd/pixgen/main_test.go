@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+const benchmarkPixRequest = `{"pixKey":"+5511999999999","merchantName":"Loja","merchantCity":"Sao Paulo","amount":"10.00"}`
+
+// BenchmarkPixHandler measures POST /pix for a repeated static charge, the case the PNG
+// cache is meant for.
+func BenchmarkPixHandler(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	cache, _ := qrcode.NewCache(0)
+	for _, bc := range []struct {
+		name  string
+		cache *qrcode.Cache
+	}{
+		{"uncached", nil},
+		{"cached", cache},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			handler := newPixHandler(nil, bc.cache, nil)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				rec := httptest.NewRecorder()
+				handler(rec, httptest.NewRequest(http.MethodPost, "/pix", strings.NewReader(benchmarkPixRequest)))
+				if rec.Code != http.StatusOK {
+					b.Fatalf("unexpected status %d: %s", rec.Code, rec.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"container/list"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thiagozs/go-pixgen/pix"
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+// pageEntry is a charge created by POST /pix and rendered by GET /pix/{id}/page. It keeps
+// only what the page needs, not the request.
+type pageEntry struct {
+	id        string
+	parsed    *pix.ParsedPayload
+	level     qrcode.ErrorCorrectionLevel
+	logo      *qrcode.Logo
+	expiresAt *time.Time
+}
+
+// pageStore keeps charges in memory, evicting the least recently viewed beyond capacity.
+type pageStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+func newPageStore(capacity int) *pageStore {
+	return &pageStore{capacity: capacity, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// add stores the charge with the expiry shown on its page and returns its id, a random
+// 128-bit hex string.
+func (s *pageStore) add(params pixParams, parsed *pix.ParsedPayload, expiresAt *time.Time) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	e := &pageEntry{
+		id:        hex.EncodeToString(b[:]),
+		parsed:    parsed,
+		level:     params.Style.Level,
+		logo:      params.Style.Logo,
+		expiresAt: expiresAt,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[e.id] = s.order.PushFront(e)
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*pageEntry).id)
+	}
+	return e.id, nil
+}
+
+func (s *pageStore) get(id string) (*pageEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[id]
+	if !ok {
+		return nil, false
+	}
+	s.order.MoveToFront(el)
+	return el.Value.(*pageEntry), true
+}
+
+// newPageHandler serves GET /pix/{id}/page, the checkout page of a charge created by
+// POST /pix.
+func newPageHandler(store *pageStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/pix/")
+		if !strings.HasSuffix(id, "/page") {
+			http.NotFound(w, r)
+			return
+		}
+		id = strings.TrimSuffix(id, "/page")
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		entry, ok := store.get(id)
+		if !ok {
+			http.Error(w, "charge not found", http.StatusNotFound)
+			return
+		}
+
+		page, err := pix.NewHTML(entry.parsed, pix.HTMLOptions{
+			ExpiresAt: entry.expiresAt,
+			Level:     entry.level,
+			Logo:      entry.logo,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if _, err := w.Write(page); err != nil {
+			log.Printf("write page response: %v", err)
+		}
+	}
+}
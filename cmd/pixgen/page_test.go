@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPageStoreEvictsLeastRecentlyViewed(t *testing.T) {
+	store := newPageStore(2)
+	first, err := store.add(pixParams{}, nil, nil)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	second, _ := store.add(pixParams{}, nil, nil)
+
+	// viewing the first charge keeps it over the second one
+	if _, ok := store.get(first); !ok {
+		t.Fatalf("expected the first charge")
+	}
+	third, _ := store.add(pixParams{}, nil, nil)
+
+	for id, want := range map[string]bool{first: true, second: false, third: true} {
+		if _, ok := store.get(id); ok != want {
+			t.Fatalf("charge %s: stored %v, want %v", id, ok, want)
+		}
+	}
+}
+
+func TestPageExpiresAtFromRequest(t *testing.T) {
+	store := newPageStore(4)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pix", newPixHandler(nil, nil, store))
+	mux.HandleFunc("/pix/", newPageHandler(store))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	expiresAt := time.Date(2030, 1, 2, 15, 4, 0, 0, time.UTC)
+	body := `{"pixKey":"+5511999999999","merchantName":"Loja","merchantCity":"Sao Paulo",` +
+		`"amount":"10.00","format":"svg","expiresAt":"` + expiresAt.Format(time.RFC3339) + `"}`
+	resp, err := http.Post(srv.URL+"/pix", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	var created pixResponse
+	err = json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK || created.PageURL == "" {
+		t.Fatalf("unexpected response %d %+v (%v)", resp.StatusCode, created, err)
+	}
+
+	resp, err = http.Get(srv.URL + created.PageURL)
+	if err != nil {
+		t.Fatalf("get page: %v", err)
+	}
+	defer resp.Body.Close()
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read page: %v", err)
+	}
+	if want := `data-expires="` + expiresAt.Format(time.RFC3339) + `"`; !strings.Contains(string(page), want) {
+		t.Fatalf("expected %s in the page", want)
+	}
+}
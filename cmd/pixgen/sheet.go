@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/thiagozs/go-pixgen/internal/bindings"
+	"github.com/thiagozs/go-pixgen/pix"
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+// sheetColumns are the CSV columns understood by the sheet command. Empty cells fall back
+// to the flags, so a file with only a txid column is enough for one code per table.
+var sheetColumns = map[string]bool{"label": true, "txid": true, "amount": true, "description": true, "payload": true}
+
+func newSheetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sheet",
+		Short: "Render a printable sheet with one labeled Pix QR code per CSV row",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			path := flags.Lookup("csv").Value.String()
+			if path == "" {
+				return errors.New("sheet requires --csv (columns: label, txid, amount, description, payload)")
+			}
+			out := flags.Lookup("out").Value.String()
+			if out == "" {
+				return errors.New("sheet requires --out")
+			}
+			format := strings.ToLower(flags.Lookup("format").Value.String())
+			if format != bindings.FormatPDF && format != bindings.FormatSVG {
+				return errors.New("invalid sheet format (expected pdf or svg)")
+			}
+
+			req, profiles, err := collectPixRequest(cmd)
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			items, err := readSheetCSV(f, req, profiles)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+
+			opts := qrcode.SheetOptions{CutLines: flags.Lookup("cut-lines").Value.String() == "true"}
+			for name, dst := range map[string]*int{"rows": &opts.Rows, "columns": &opts.Columns} {
+				v, err := strconv.Atoi(flags.Lookup(name).Value.String())
+				if err != nil {
+					return fmt.Errorf("invalid %s: %w", name, err)
+				}
+				*dst = v
+			}
+			for name, dst := range map[string]*float64{"margin": &opts.MarginMM, "label-size": &opts.LabelSizeMM} {
+				v, err := strconv.ParseFloat(flags.Lookup(name).Value.String(), 64)
+				if err != nil {
+					return fmt.Errorf("invalid %s: %w", name, err)
+				}
+				*dst = v
+			}
+			if opts.Level, err = qrcode.ParseErrorCorrectionLevel(req.ECC); err != nil {
+				return err
+			}
+
+			if format == bindings.FormatPDF {
+				pdf, err := qrcode.NewSheetPDF(items, opts)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(out, pdf, 0o644); err != nil {
+					return fmt.Errorf("write sheet: %w", err)
+				}
+				fmt.Printf("Sheet with %d QR codes written to %s\n", len(items), out)
+				return nil
+			}
+
+			pages, err := qrcode.NewSheetSVG(items, opts)
+			if err != nil {
+				return err
+			}
+			// one SVG per page: sheet.svg, sheet-2.svg, ...
+			ext := filepath.Ext(out)
+			for i, page := range pages {
+				name := out
+				if i > 0 {
+					name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(out, ext), i+1, ext)
+				}
+				if err := os.WriteFile(name, page, 0o644); err != nil {
+					return fmt.Errorf("write sheet: %w", err)
+				}
+				fmt.Printf("Sheet page %d written to %s\n", i+1, name)
+			}
+			return nil
+		},
+	}
+
+	addPixFlags(cmd)
+	flags := cmd.Flags()
+	flags.String("csv", "", "CSV file with a header row: label, txid, amount, description and/or payload")
+	flags.String("out", "", "Output file (SVG sheets write one file per page)")
+	flags.String("format", bindings.FormatPDF, "Sheet format: pdf or svg")
+	flags.Int("rows", qrcode.DefaultSheetRows, "Rows of QR codes per page")
+	flags.Int("columns", qrcode.DefaultSheetColumns, "Columns of QR codes per page")
+	flags.Float64("margin", qrcode.DefaultSheetMarginMM, "Page margin in millimeters")
+	flags.Float64("label-size", qrcode.DefaultSheetLabelMM, "Label font size in millimeters")
+	flags.Bool("cut-lines", false, "Draw dashed cut lines between the codes")
+	flags.String("ecc", "M", "QR code error correction level: L, M, Q or H")
+
+	return cmd
+}
+
+// readSheetCSV builds one sheet item per CSV row: the payload column as is, or a payload
+// generated from the flags with the row txid, amount and description. The label defaults
+// to the TxID, then to the row number.
+func readSheetCSV(r io.Reader, req pixRequest, profiles pix.Profiles) ([]qrcode.SheetItem, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !sheetColumns[name] {
+			return nil, fmt.Errorf("unknown csv column %q (expected label, txid, amount, description or payload)", name)
+		}
+		index[name] = i
+	}
+	cell := func(row []string, name string) string {
+		if i, ok := index[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var items []qrcode.SheetItem
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rowReq := req
+		for name, dst := range map[string]*string{"txid": &rowReq.TxID, "amount": &rowReq.Amount, "description": &rowReq.Description} {
+			if v := cell(row, name); v != "" {
+				*dst = v
+			}
+		}
+		parsed, err := resolvePayload(rowReq, cell(row, "payload"), profiles)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		label := cell(row, "label")
+		if label == "" {
+			label = parsed.AdditionalDataField.TxID
+		}
+		if label == "" || label == "***" {
+			label = strconv.Itoa(len(items) + 1)
+		}
+		items = append(items, qrcode.SheetItem{Content: parsed.Raw, Label: label})
+	}
+	if len(items) == 0 {
+		return nil, errors.New("csv has no rows")
+	}
+	return items, nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thiagozs/go-pixgen/internal/bindings"
+	"github.com/thiagozs/go-pixgen/pix"
+)
+
+const sheetSamplePayload = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestReadSheetCSV(t *testing.T) {
+	base := pixRequest{Request: bindings.Request{PixKey: "+5511999999999", MerchantName: "Loja", MerchantCity: "Sao Paulo", Amount: "5.00"}}
+
+	type row struct {
+		label, amount, txid string
+	}
+	tests := []struct {
+		name string
+		csv  string
+		want []row
+		err  string
+	}{
+		{
+			name: "bom header",
+			csv:  "\ufefflabel,amount\nMesa 1,10.00\n",
+			want: []row{{"Mesa 1", "10.00", "***"}},
+		},
+		{
+			name: "flag fallback for empty cells",
+			csv:  "label,txid,amount\nMesa 2,PED2,\n",
+			want: []row{{"Mesa 2", "5.00", "PED2"}},
+		},
+		{
+			name: "label falls back to txid",
+			csv:  "txid,amount\nPED3,7.50\n",
+			want: []row{{"PED3", "7.50", "PED3"}},
+		},
+		{
+			name: "label falls back to row number",
+			csv:  "amount,description\n1.00,a\n2.00,b\n",
+			want: []row{{"1", "1.00", "***"}, {"2", "2.00", "***"}},
+		},
+		{
+			name: "payload column",
+			csv:  "label,payload\nBacen," + sheetSamplePayload + "\n",
+			want: []row{{"Bacen", "", "***"}},
+		},
+		{
+			name: "unknown column",
+			csv:  "label,price\nMesa,1.00\n",
+			err:  `unknown csv column "price"`,
+		},
+		{
+			name: "line numbered error",
+			csv:  "txid,amount\nOK1,1.00\nBAD,abc\n",
+			err:  "line 3:",
+		},
+		{
+			name: "no rows",
+			csv:  "label,amount\n",
+			err:  "csv has no rows",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := readSheetCSV(strings.NewReader(tt.csv), base, nil)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("read csv: %v", err)
+			}
+			if len(items) != len(tt.want) {
+				t.Fatalf("expected %d items, got %d", len(tt.want), len(items))
+			}
+			for i, want := range tt.want {
+				parsed, err := pix.ParsePayload(items[i].Content)
+				if err != nil {
+					t.Fatalf("item %d: parse payload: %v", i, err)
+				}
+				got := row{items[i].Label, parsed.TransactionAmount, parsed.AdditionalDataField.TxID}
+				if got != want {
+					t.Fatalf("item %d: got %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -3,14 +3,16 @@ module github.com/thiagozs/go-pixgen
 go 1.17
 
 require (
+	github.com/makiuchi-d/gozxing v0.1.1
 	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
-	github.com/snksoft/crc v1.1.0
 	github.com/spf13/cobra v1.7.0
+	golang.org/x/image v0.12.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/mdp/qrterminal v1.0.1 // indirect
-	rsc.io/qr v0.2.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 )
 
 replace github.com/spf13/cobra => ./internal/cobra
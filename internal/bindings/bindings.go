@@ -0,0 +1,337 @@
+// Package bindings implements the JSON API shared by the REST service (cmd/pixgen), the
+// WebAssembly and the C library entry points: requests mirror the body of POST /pix and
+// responses mirror its reply, so every front-end builds the same payloads through New.
+package bindings
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/thiagozs/go-pixgen/assets"
+	"github.com/thiagozs/go-pixgen/pix"
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+// Request is the JSON input of the bindings, the REST request without profiles.
+type Request struct {
+	Kind           string `json:"kind"`
+	PixKey         string `json:"pixKey"`
+	URL            string `json:"url"`
+	MerchantName   string `json:"merchantName"`
+	MerchantCity   string `json:"merchantCity"`
+	Amount         string `json:"amount"`
+	Description    string `json:"description"`
+	AdditionalInfo string `json:"additionalInfo"`
+	TxID           string `json:"txid"`
+	// Format is png (default), svg, pdf, zpl or html.
+	Format      string `json:"format,omitempty"`
+	ECC         string `json:"ecc,omitempty"`
+	Foreground  string `json:"foreground,omitempty"`
+	Background  string `json:"background,omitempty"`
+	Transparent bool   `json:"transparent,omitempty"`
+	QuietZone   *int   `json:"quietZone,omitempty"`
+	// Size is the PNG side in pixels.
+	Size int `json:"size,omitempty"`
+	// Logo is "pix" for the built-in Pix logo or a base64-encoded PNG, JPEG or SVG image.
+	Logo         string  `json:"logo,omitempty"`
+	LogoCoverage float64 `json:"logoCoverage,omitempty"`
+}
+
+// Response is the JSON output of Generate; QRCode holds the base64 encoded image.
+type Response struct {
+	Payload    string             `json:"payload"`
+	QRCode     string             `json:"qrCode"`
+	Format     string             `json:"format"`
+	QRMetadata qrcode.Metadata    `json:"qrMetadata"`
+	Kind       string             `json:"kind"`
+	TxID       string             `json:"txid"`
+	Parsed     *pix.ParsedPayload `json:"parsed"`
+}
+
+// Validation is the JSON output of Validate.
+type Validation struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// Output formats accepted by ParseFormat and Render.
+const (
+	FormatPNG  = "png"
+	FormatSVG  = "svg"
+	FormatPDF  = "pdf"
+	FormatZPL  = "zpl"
+	FormatHTML = "html"
+)
+
+// BuiltinLogo selects the Pix logo embedded in the binary.
+const BuiltinLogo = "pix"
+
+// Style is the parsed QR Code styling of a request. Level and Logo are exposed for the
+// renderers that draw the QR Code themselves (ZPL labels, HTML pages and cards).
+type Style struct {
+	Level qrcode.ErrorCorrectionLevel
+	Logo  *qrcode.Logo
+
+	options []pix.Options
+}
+
+// RenderOptions holds the settings of the PDF and ZPL renderers; zero values use their
+// defaults. The error correction level of the ZPL label comes from the Style.
+type RenderOptions struct {
+	PDF qrcode.PDFOptions
+	ZPL pix.ZPLOptions
+}
+
+// DecodeRequest decodes a JSON request.
+func DecodeRequest(data []byte) (Request, error) {
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return Request{}, fmt.Errorf("invalid json: %w", err)
+	}
+	return req, nil
+}
+
+// Generate builds the payload and the QR Code of req in the requested format, with the
+// default PDF and ZPL settings.
+func Generate(req Request) (*Response, error) {
+	format, err := ParseFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+	style, err := ParseStyle(req)
+	if err != nil {
+		return nil, err
+	}
+	p, err := New(req)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := p.GenPayload()
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := pix.ParsePayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	qr, err := Render(p, parsed, format, style, RenderOptions{})
+	if err != nil {
+		return nil, err
+	}
+	meta, err := p.QRCodeMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		Payload:    payload,
+		QRCode:     base64.StdEncoding.EncodeToString(qr),
+		Format:     format,
+		QRMetadata: meta,
+		Kind:       parsed.Kind().String(),
+		TxID:       parsed.AdditionalDataField.TxID,
+		Parsed:     parsed,
+	}, nil
+}
+
+// Validate checks req without rendering anything; problems are reported in the result
+// rather than as an error.
+func Validate(req Request) Validation {
+	format, err := ParseFormat(req.Format)
+	if err != nil {
+		return Validation{Error: err.Error()}
+	}
+	style, err := ParseStyle(req)
+	if err != nil {
+		return Validation{Error: err.Error()}
+	}
+	if err := checkFormat(format, style); err != nil {
+		return Validation{Error: err.Error()}
+	}
+	if _, err := New(req); err != nil {
+		return Validation{Error: err.Error()}
+	}
+	return Validation{Valid: true}
+}
+
+// SVG renders the QR Code of req as SVG markup.
+func SVG(req Request) ([]byte, error) {
+	p, err := New(req)
+	if err != nil {
+		return nil, err
+	}
+	return p.GenQRCodeSVG()
+}
+
+// PNG renders the QR Code of req as a PNG image.
+func PNG(req Request) ([]byte, error) {
+	p, err := New(req)
+	if err != nil {
+		return nil, err
+	}
+	return p.GenQRCode()
+}
+
+// New converts req into functional options and creates the Pix; opts are applied after the
+// options of req, for settings a front-end does not take from the request (ASCII output,
+// render caches).
+func New(req Request, opts ...pix.Options) (*pix.Pix, error) {
+	kind, err := pix.ParseKind(req.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if req.MerchantName == "" {
+		return nil, errors.New("merchantName is required")
+	}
+	if req.MerchantCity == "" {
+		return nil, errors.New("merchantCity is required")
+	}
+	if kind == pix.STATIC && req.PixKey == "" {
+		return nil, errors.New("pixKey is required for static payloads")
+	}
+	if kind == pix.DYNAMIC && req.URL == "" {
+		return nil, errors.New("url is required for dynamic payloads")
+	}
+
+	reqOpts := []pix.Options{
+		pix.OptKind(kind),
+		pix.OptMerchantName(req.MerchantName),
+		pix.OptMerchantCity(req.MerchantCity),
+	}
+	if req.PixKey != "" {
+		reqOpts = append(reqOpts, pix.OptPixKey(req.PixKey))
+	}
+	if req.URL != "" {
+		reqOpts = append(reqOpts, pix.OptUrl(req.URL))
+	}
+	if req.Amount != "" {
+		reqOpts = append(reqOpts, pix.OptAmount(req.Amount))
+	}
+	if req.Description != "" {
+		reqOpts = append(reqOpts, pix.OptDescription(req.Description))
+	}
+	if req.AdditionalInfo != "" {
+		reqOpts = append(reqOpts, pix.OptAdditionalInfo(req.AdditionalInfo))
+	}
+	if req.TxID != "" {
+		reqOpts = append(reqOpts, pix.OptTxId(req.TxID))
+	}
+
+	style, err := ParseStyle(req)
+	if err != nil {
+		return nil, err
+	}
+	reqOpts = append(reqOpts, style.options...)
+	return pix.New(append(reqOpts, opts...)...)
+}
+
+// Render renders the QR Code of p in format: an image (png, svg or pdf), a ZPL label or an
+// HTML checkout page for parsed, its payload.
+func Render(p *pix.Pix, parsed *pix.ParsedPayload, format string, style Style, opts RenderOptions) ([]byte, error) {
+	if err := checkFormat(format, style); err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatSVG:
+		return p.GenQRCodeSVG()
+	case FormatPDF:
+		return p.GenQRCodePDF(opts.PDF)
+	case FormatZPL:
+		zpl := opts.ZPL
+		zpl.Level = style.Level
+		return pix.NewZPL(parsed, zpl)
+	case FormatHTML:
+		return pix.NewHTML(parsed, pix.HTMLOptions{Level: style.Level, Logo: style.Logo})
+	default:
+		return p.GenQRCode()
+	}
+}
+
+// ParseFormat normalizes an output format; empty means png.
+func ParseFormat(format string) (string, error) {
+	switch f := strings.ToLower(strings.TrimSpace(format)); f {
+	case "", FormatPNG:
+		return FormatPNG, nil
+	case FormatSVG, FormatPDF, FormatZPL, FormatHTML:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid format %q (expected png, svg, pdf, zpl or html)", format)
+	}
+}
+
+// checkFormat rejects styles the format cannot render.
+func checkFormat(format string, style Style) error {
+	if style.Logo != nil && (format == FormatPDF || format == FormatZPL) {
+		return fmt.Errorf("logo is not supported in %s output", format)
+	}
+	return nil
+}
+
+// ParseStyle parses the QR Code styling of req: size, error correction level, colors,
+// quiet zone and logo.
+func ParseStyle(req Request) (Style, error) {
+	var style Style
+
+	if req.Size < 0 {
+		return Style{}, errors.New("size must not be negative")
+	}
+	if req.Size > 0 {
+		style.options = append(style.options, pix.OptQRCodeSize(req.Size))
+	}
+	level, err := qrcode.ParseErrorCorrectionLevel(req.ECC)
+	if err != nil {
+		return Style{}, err
+	}
+	style.Level = level
+	style.options = append(style.options, pix.OptQRCodeErrorCorrection(level))
+
+	if req.Foreground != "" || req.Background != "" {
+		var fg, bg color.Color
+		if req.Foreground != "" {
+			c, err := qrcode.ParseHexColor(req.Foreground)
+			if err != nil {
+				return Style{}, err
+			}
+			fg = c
+		}
+		if req.Background != "" {
+			c, err := qrcode.ParseHexColor(req.Background)
+			if err != nil {
+				return Style{}, err
+			}
+			bg = c
+		}
+		style.options = append(style.options, pix.OptQRCodeColors(fg, bg))
+	}
+	if req.Transparent {
+		style.options = append(style.options, pix.OptQRCodeTransparentBackground(true))
+	}
+	if req.QuietZone != nil {
+		if *req.QuietZone < 0 {
+			return Style{}, errors.New("quiet zone must not be negative")
+		}
+		style.options = append(style.options, pix.OptQRCodeQuietZone(*req.QuietZone))
+	}
+
+	if req.Logo != "" {
+		logo := qrcode.Logo{Coverage: req.LogoCoverage}
+		if strings.EqualFold(req.Logo, BuiltinLogo) {
+			logo.Data = assets.PixLogo
+		} else {
+			data, err := base64.StdEncoding.DecodeString(req.Logo)
+			if err != nil {
+				return Style{}, fmt.Errorf("invalid logo: %w", err)
+			}
+			logo.Data = data
+		}
+		style.Logo = &logo
+		style.options = append(style.options, pix.OptQRCodeLogo(logo))
+	}
+
+	return style, nil
+}
@@ -0,0 +1,181 @@
+package bindings
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/thiagozs/go-pixgen/pix"
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+const testRequest = `{
+	"pixKey": "+5511999999999",
+	"merchantName": "Loja Exemplo",
+	"merchantCity": "Sao Paulo",
+	"amount": "12.50",
+	"txid": "PEDIDO123",
+	"ecc": "q"
+}`
+
+func TestGenerate(t *testing.T) {
+	req, err := DecodeRequest([]byte(testRequest))
+	if err != nil {
+		t.Fatalf("decode request: %v", err)
+	}
+	resp, err := Generate(req)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if resp.Format != "png" || resp.Kind != "STATIC" || resp.TxID != "PEDIDO123" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if resp.Parsed.TransactionAmount != "12.50" || resp.QRMetadata.Level != qrcode.Quartile {
+		t.Fatalf("unexpected parsed payload %+v / %+v", resp.Parsed, resp.QRMetadata)
+	}
+	data, err := base64.StdEncoding.DecodeString(resp.QRCode)
+	if err != nil {
+		t.Fatalf("decode qr code: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	codes, err := qrcode.Decode(img)
+	if err != nil || len(codes) != 1 || codes[0].Content != resp.Payload {
+		t.Fatalf("unexpected qr codes %+v (%v)", codes, err)
+	}
+
+	// the response encodes like the REST reply
+	out, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("encode response: %v", err)
+	}
+	for _, key := range []string{`"payload":`, `"qrCode":`, `"qrMetadata":`, `"parsed":`} {
+		if !bytes.Contains(out, []byte(key)) {
+			t.Fatalf("expected %s in %s", key, out)
+		}
+	}
+
+	req.Format = "svg"
+	resp, err = Generate(req)
+	if err != nil {
+		t.Fatalf("generate svg: %v", err)
+	}
+	svg, _ := base64.StdEncoding.DecodeString(resp.QRCode)
+	if resp.Format != "svg" || !bytes.Contains(svg, []byte("<svg")) {
+		t.Fatalf("expected svg output, got %q", svg)
+	}
+
+	for format, prefix := range map[string]string{"pdf": "%PDF-", "zpl": "^XA", "html": "<!DOCTYPE html>"} {
+		req.Format = format
+		resp, err = Generate(req)
+		if err != nil {
+			t.Fatalf("generate %s: %v", format, err)
+		}
+		out, _ := base64.StdEncoding.DecodeString(resp.QRCode)
+		if resp.Format != format || !bytes.HasPrefix(out, []byte(prefix)) {
+			t.Fatalf("expected %s output, got %.40q", format, out)
+		}
+	}
+}
+
+func TestNewExtraOptions(t *testing.T) {
+	req, err := DecodeRequest([]byte(testRequest))
+	if err != nil {
+		t.Fatalf("decode request: %v", err)
+	}
+	// options passed to New apply after those of the request
+	p, err := New(req, pix.OptQRCodeErrorCorrection(qrcode.High))
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	meta, err := p.QRCodeMetadata()
+	if err != nil {
+		t.Fatalf("metadata: %v", err)
+	}
+	if meta.Level != qrcode.High {
+		t.Fatalf("expected level H, got %v", meta.Level)
+	}
+
+	style, err := ParseStyle(req)
+	if err != nil || style.Level != qrcode.Quartile || style.Logo != nil {
+		t.Fatalf("unexpected style %+v (%v)", style, err)
+	}
+	req.Logo = BuiltinLogo
+	if style, err = ParseStyle(req); err != nil || style.Logo == nil {
+		t.Fatalf("expected the built-in logo, got %+v (%v)", style, err)
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	req, err := DecodeRequest([]byte(testRequest))
+	if err != nil {
+		t.Fatalf("decode request: %v", err)
+	}
+	req.Size = 200
+	req.Foreground = "#004d40"
+	req.Logo = "pix"
+
+	data, err := PNG(req)
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	if w := img.Bounds().Dx(); w > 200 {
+		t.Fatalf("expected at most 200 pixels, got %d", w)
+	}
+	svg, err := SVG(req)
+	if err != nil {
+		t.Fatalf("svg: %v", err)
+	}
+	if !strings.Contains(string(svg), "<image") {
+		t.Fatalf("expected the logo in the svg")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	req, err := DecodeRequest([]byte(testRequest))
+	if err != nil {
+		t.Fatalf("decode request: %v", err)
+	}
+	if v := Validate(req); !v.Valid || v.Error != "" {
+		t.Fatalf("expected a valid request, got %+v", v)
+	}
+
+	quiet := -1
+	for name, mutate := range map[string]func(*Request){
+		"merchant":   func(r *Request) { r.MerchantName = "" },
+		"kind":       func(r *Request) { r.Kind = "other" },
+		"dynamic":    func(r *Request) { r.Kind = "dynamic" },
+		"amount":     func(r *Request) { r.Amount = "12,50" },
+		"format":     func(r *Request) { r.Format = "gif" },
+		"ecc":        func(r *Request) { r.ECC = "x" },
+		"contrast":   func(r *Request) { r.Foreground = "#ffffff" },
+		"quiet zone": func(r *Request) { r.QuietZone = &quiet },
+		"logo":       func(r *Request) { r.Logo = "not base64!" },
+		"pdf logo":   func(r *Request) { r.Format, r.Logo = "pdf", "pix" },
+	} {
+		r := req
+		mutate(&r)
+		if v := Validate(r); v.Valid || v.Error == "" {
+			t.Fatalf("%s: expected an invalid request, got %+v", name, v)
+		}
+		if _, err := Generate(r); err == nil {
+			t.Fatalf("%s: expected generate to fail", name)
+		}
+	}
+
+	if _, err := DecodeRequest([]byte(`{"pixKey":`)); err == nil {
+		t.Fatalf("expected invalid json to be rejected")
+	}
+	if _, err := pix.ParsePayload("not a payload"); err == nil {
+		t.Fatalf("expected invalid payloads to be rejected")
+	}
+}
@@ -0,0 +1,244 @@
+// Package pkcs7 produces the detached PKCS#7 (CMS) SignedData signatures required by Apple
+// Wallet passes, with just enough parsing to verify them in tests and tools.
+package pkcs7
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+var (
+	oidData            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidContentType     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidMessageDigest   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidSHA256          = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidRSAEncryption   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+)
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+// detachedContentInfo is the encapsulated content of a detached signature: the type only.
+type detachedContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      detachedContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type signerInfo struct {
+	Version                   int
+	IssuerAndSerialNumber     issuerAndSerialNumber
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+}
+
+type attribute struct {
+	Type  asn1.ObjectIdentifier
+	Value asn1.RawValue
+}
+
+// SignDetached signs content with key and returns the DER encoded SignedData, without the
+// content itself. The signer certificate and the intermediates (for Wallet passes, the
+// Apple WWDR certificate) are embedded; the signed attributes carry the content type,
+// signing time and SHA-256 message digest. RSA and ECDSA keys are supported.
+func SignDetached(content []byte, cert *x509.Certificate, key crypto.Signer, intermediates []*x509.Certificate, signingTime time.Time) ([]byte, error) {
+	if cert == nil || key == nil {
+		return nil, errors.New("pkcs7: a certificate and a private key are required")
+	}
+	var sigAlg pkix.AlgorithmIdentifier
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		sigAlg = pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue}
+	case *ecdsa.PublicKey:
+		sigAlg = pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256}
+	default:
+		return nil, fmt.Errorf("pkcs7: unsupported key type %T", pub)
+	}
+	if !publicKeysEqual(cert.PublicKey, key.Public()) {
+		return nil, errors.New("pkcs7: the private key does not match the certificate")
+	}
+
+	digest := sha256.Sum256(content)
+	attrs, err := marshalAttributes(
+		attributeOf(oidContentType, oidData),
+		attributeOf(oidMessageDigest, digest[:]),
+		attributeOf(oidSigningTime, signingTime.UTC()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	// the signature covers the attributes encoded as a SET OF, not with their [0] tag
+	signed, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: attrs})
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(signed)
+	signature, err := key.Sign(rand.Reader, h[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs7: sign: %w", err)
+	}
+
+	var certs []byte
+	for _, c := range append([]*x509.Certificate{cert}, intermediates...) {
+		certs = append(certs, c.Raw...)
+	}
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{{Algorithm: oidSHA256}},
+		ContentInfo:      detachedContentInfo{ContentType: oidData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certs},
+		SignerInfos: []signerInfo{{
+			Version: 1,
+			IssuerAndSerialNumber: issuerAndSerialNumber{
+				Issuer:       asn1.RawValue{FullBytes: cert.RawIssuer},
+				SerialNumber: cert.SerialNumber,
+			},
+			DigestAlgorithm:           pkix.AlgorithmIdentifier{Algorithm: oidSHA256},
+			AuthenticatedAttributes:   asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrs},
+			DigestEncryptionAlgorithm: sigAlg,
+			EncryptedDigest:           signature,
+		}},
+	}
+	inner, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs7: marshal signed data: %w", err)
+	}
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: inner},
+	})
+}
+
+// VerifyDetached checks a signature made by SignDetached (or openssl smime -sign -binary)
+// over content and returns the signer certificate. It verifies the message digest and the
+// signature against the embedded certificate; the certificate chain is not validated.
+func VerifyDetached(signature, content []byte) (*x509.Certificate, error) {
+	var ci contentInfo
+	if rest, err := asn1.Unmarshal(signature, &ci); err != nil || len(rest) > 0 {
+		return nil, fmt.Errorf("pkcs7: invalid content info: %v", err)
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		return nil, errors.New("pkcs7: not a signed data structure")
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return nil, fmt.Errorf("pkcs7: invalid signed data: %w", err)
+	}
+	if len(sd.SignerInfos) != 1 {
+		return nil, fmt.Errorf("pkcs7: expected one signer, got %d", len(sd.SignerInfos))
+	}
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs7: invalid certificates: %w", err)
+	}
+
+	si := sd.SignerInfos[0]
+	var signer *x509.Certificate
+	for _, c := range certs {
+		if bytes.Equal(c.RawIssuer, si.IssuerAndSerialNumber.Issuer.FullBytes) && c.SerialNumber.Cmp(si.IssuerAndSerialNumber.SerialNumber) == 0 {
+			signer = c
+		}
+	}
+	if signer == nil {
+		return nil, errors.New("pkcs7: signer certificate not found")
+	}
+	if !si.DigestAlgorithm.Algorithm.Equal(oidSHA256) {
+		return nil, fmt.Errorf("pkcs7: unsupported digest algorithm %v", si.DigestAlgorithm.Algorithm)
+	}
+
+	var attrs []attribute
+	if _, err := asn1.UnmarshalWithParams(si.AuthenticatedAttributes.FullBytes, &attrs, "set,tag:0"); err != nil {
+		return nil, fmt.Errorf("pkcs7: invalid signed attributes: %w", err)
+	}
+	digest := sha256.Sum256(content)
+	found := false
+	for _, a := range attrs {
+		if !a.Type.Equal(oidMessageDigest) {
+			continue
+		}
+		var value []byte
+		if _, err := asn1.Unmarshal(a.Value.Bytes, &value); err != nil {
+			return nil, fmt.Errorf("pkcs7: invalid message digest: %w", err)
+		}
+		if !bytes.Equal(value, digest[:]) {
+			return nil, errors.New("pkcs7: message digest does not match the content")
+		}
+		found = true
+	}
+	if !found {
+		return nil, errors.New("pkcs7: missing message digest attribute")
+	}
+
+	signed := append([]byte(nil), si.AuthenticatedAttributes.FullBytes...)
+	signed[0] = 0x31 // SET OF
+	alg := x509.SHA256WithRSA
+	if si.DigestEncryptionAlgorithm.Algorithm.Equal(oidECDSAWithSHA256) {
+		alg = x509.ECDSAWithSHA256
+	}
+	if err := signer.CheckSignature(alg, signed, si.EncryptedDigest); err != nil {
+		return nil, fmt.Errorf("pkcs7: %w", err)
+	}
+	return signer, nil
+}
+
+func attributeOf(typ asn1.ObjectIdentifier, value interface{}) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		v, err := asn1.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return asn1.Marshal(attribute{
+			Type:  typ,
+			Value: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: v},
+		})
+	}
+}
+
+// marshalAttributes encodes the attributes in DER SET OF order (sorted encodings).
+func marshalAttributes(attrs ...func() ([]byte, error)) ([]byte, error) {
+	encoded := make([][]byte, len(attrs))
+	for i, attr := range attrs {
+		b, err := attr()
+		if err != nil {
+			return nil, fmt.Errorf("pkcs7: marshal attribute: %w", err)
+		}
+		encoded[i] = b
+	}
+	sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+	return bytes.Join(encoded, nil), nil
+}
+
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	k, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && k.Equal(b)
+}
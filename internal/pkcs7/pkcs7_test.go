@@ -0,0 +1,76 @@
+package pkcs7
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func testCertificate(t *testing.T, key crypto.Signer, name string) *x509.Certificate {
+	t.Helper()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return cert
+}
+
+func TestSignDetached(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa key: %v", err)
+	}
+	intermediate := testCertificate(t, ecKey, "Intermediate")
+	content := []byte(`{"pass.json":"da39a3ee5e6b4b0d3255bfef95601890afd80709"}`)
+
+	for name, key := range map[string]crypto.Signer{"rsa": rsaKey, "ecdsa": ecKey} {
+		cert := testCertificate(t, key, "Pass Type ID: pass.com.example.pix")
+		sig, err := SignDetached(content, cert, key, []*x509.Certificate{intermediate}, time.Now())
+		if err != nil {
+			t.Fatalf("%s: sign: %v", name, err)
+		}
+		signer, err := VerifyDetached(sig, content)
+		if err != nil {
+			t.Fatalf("%s: verify: %v", name, err)
+		}
+		if signer.Subject.CommonName != cert.Subject.CommonName {
+			t.Fatalf("%s: unexpected signer %s", name, signer.Subject)
+		}
+		if _, err := VerifyDetached(sig, append(content, ' ')); err == nil {
+			t.Fatalf("%s: expected modified content to fail verification", name)
+		}
+	}
+
+	cert := testCertificate(t, rsaKey, "RSA")
+	if _, err := SignDetached(content, cert, ecKey, nil, time.Now()); err == nil {
+		t.Fatalf("expected a key not matching the certificate to be rejected")
+	}
+	if _, err := SignDetached(content, nil, rsaKey, nil, time.Now()); err == nil {
+		t.Fatalf("expected a missing certificate to be rejected")
+	}
+	if _, err := VerifyDetached([]byte("not der"), content); err == nil {
+		t.Fatalf("expected invalid signatures to be rejected")
+	}
+}
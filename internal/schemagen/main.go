@@ -0,0 +1,35 @@
+// Command schemagen writes the JSON Schemas of the pix JSON representations.
+// It is invoked through go generate from the pix package.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/thiagozs/go-pixgen/pix"
+)
+
+func main() {
+	dir := flag.String("dir", "docs/schema", "output directory")
+	flag.Parse()
+
+	schemas := map[string]func() ([]byte, error){
+		"parsed-payload.schema.json":  pix.ParsedPayloadSchema,
+		"dynamic-payload.schema.json": pix.DynamicPayloadSchema,
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		log.Fatalf("create output dir: %v", err)
+	}
+	for name, gen := range schemas {
+		data, err := gen()
+		if err != nil {
+			log.Fatalf("generate %s: %v", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(*dir, name), data, 0o644); err != nil {
+			log.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Package ndef encodes Pix payloads as NFC Data Exchange Format (NDEF) messages, the
+// format read by phones from NFC Forum tags such as NTAG213/215/216 stickers, and parses
+// them back.
+package ndef
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// TNF is the Type Name Format of a record, telling how its Type is interpreted.
+type TNF byte
+
+const (
+	TNFEmpty       TNF = 0x00
+	TNFWellKnown   TNF = 0x01 // NFC Forum RTD types such as "T" (text) and "U" (URI)
+	TNFMedia       TNF = 0x02 // RFC 2046 media type such as "text/plain"
+	TNFAbsoluteURI TNF = 0x03
+	TNFExternal    TNF = 0x04 // NFC Forum external type "domain:type"
+	TNFUnknown     TNF = 0x05
+	TNFUnchanged   TNF = 0x06 // middle and last chunks of a chunked payload
+)
+
+// record header flags
+const (
+	flagMB  = 0x80 // message begin
+	flagME  = 0x40 // message end
+	flagCF  = 0x20 // chunk flag
+	flagSR  = 0x10 // short record, 1 byte payload length
+	flagIL  = 0x08 // ID length present
+	tnfMask = 0x07
+)
+
+// Well-known record types.
+var (
+	TypeText = []byte("T")
+	TypeURI  = []byte("U")
+)
+
+// uriPrefixes are the URI identifier codes of the NFC Forum URI RTD; the index is the code
+// stored in the first payload byte.
+var uriPrefixes = []string{
+	"", "http://www.", "https://www.", "http://", "https://", "tel:", "mailto:",
+	"ftp://anonymous:anonymous@", "ftp://ftp.", "ftps://", "sftp://", "smb://", "nfs://",
+	"ftp://", "dav://", "news:", "telnet://", "imap:", "rtsp://", "urn:", "pop:", "sip:",
+	"sips:", "tftp:", "btspp://", "btl2cap://", "btgoep://", "tcpobex://", "irdaobex://",
+	"file://", "urn:epc:id:", "urn:epc:tag:", "urn:epc:pat:", "urn:epc:raw:", "urn:epc:",
+	"urn:nfc:",
+}
+
+// Record is a single NDEF record. Chunked records are reassembled by Parse, so a Record
+// always holds its whole payload.
+type Record struct {
+	TNF     TNF
+	Type    []byte
+	ID      []byte
+	Payload []byte
+}
+
+// Message is a sequence of records, written to a tag as a single unit.
+type Message []Record
+
+// NewTextRecord builds a well-known text record ("T") with UTF-8 text and an IANA
+// language code such as "pt-BR".
+func NewTextRecord(text, lang string) (Record, error) {
+	if lang == "" || len(lang) > 0x3f {
+		return Record{}, errors.New("ndef: text record language must have 1 to 63 characters")
+	}
+	if !utf8.ValidString(text) {
+		return Record{}, errors.New("ndef: text record must be valid UTF-8")
+	}
+	payload := make([]byte, 0, 1+len(lang)+len(text))
+	payload = append(payload, byte(len(lang))) // bit 7 clear: UTF-8
+	payload = append(payload, lang...)
+	payload = append(payload, text...)
+	return Record{TNF: TNFWellKnown, Type: TypeText, Payload: payload}, nil
+}
+
+// NewURIRecord builds a well-known URI record ("U"), abbreviating the longest known
+// prefix such as "https://" to its one byte identifier code.
+func NewURIRecord(uri string) Record {
+	code := 0
+	for i, prefix := range uriPrefixes {
+		if strings.HasPrefix(uri, prefix) && len(prefix) > len(uriPrefixes[code]) {
+			code = i
+		}
+	}
+	payload := make([]byte, 0, 1+len(uri))
+	payload = append(payload, byte(code))
+	payload = append(payload, uri[len(uriPrefixes[code]):]...)
+	return Record{TNF: TNFWellKnown, Type: TypeURI, Payload: payload}
+}
+
+// NewExternalRecord builds an NFC Forum external type record. The type name has the form
+// "domain:type" (for instance "example.com:pix") and is stored lowercase, as external
+// types are case-insensitive and Android matches them in lowercase.
+func NewExternalRecord(typ string, payload []byte) (Record, error) {
+	colon := strings.IndexByte(typ, ':')
+	if colon <= 0 || colon == len(typ)-1 {
+		return Record{}, fmt.Errorf("ndef: external type %q must have the form domain:type", typ)
+	}
+	for _, r := range typ {
+		if r <= ' ' || r > '~' {
+			return Record{}, fmt.Errorf("ndef: external type %q must be printable ASCII", typ)
+		}
+	}
+	return Record{TNF: TNFExternal, Type: []byte(strings.ToLower(typ)), Payload: payload}, nil
+}
+
+// Text returns the text and language of a well-known text record.
+func (r Record) Text() (text, lang string, err error) {
+	if r.TNF != TNFWellKnown || string(r.Type) != string(TypeText) {
+		return "", "", errors.New("ndef: not a text record")
+	}
+	if len(r.Payload) == 0 {
+		return "", "", errors.New("ndef: empty text record")
+	}
+	status := r.Payload[0]
+	if status&0x80 != 0 {
+		return "", "", errors.New("ndef: UTF-16 text records are not supported")
+	}
+	n := int(status & 0x3f)
+	if 1+n > len(r.Payload) {
+		return "", "", errors.New("ndef: text record language exceeds the payload")
+	}
+	return string(r.Payload[1+n:]), string(r.Payload[1 : 1+n]), nil
+}
+
+// URI returns the expanded URI of a well-known URI record.
+func (r Record) URI() (string, error) {
+	if r.TNF != TNFWellKnown || string(r.Type) != string(TypeURI) {
+		return "", errors.New("ndef: not a uri record")
+	}
+	if len(r.Payload) == 0 {
+		return "", errors.New("ndef: empty uri record")
+	}
+	code := int(r.Payload[0])
+	if code >= len(uriPrefixes) {
+		return "", fmt.Errorf("ndef: reserved uri identifier code 0x%02x", code)
+	}
+	return uriPrefixes[code] + string(r.Payload[1:]), nil
+}
+
+// MarshalBinary encodes the message, using short records for payloads up to 255 bytes.
+func (m Message) MarshalBinary() ([]byte, error) {
+	if len(m) == 0 {
+		return nil, errors.New("ndef: empty message")
+	}
+	var out []byte
+	for i, r := range m {
+		if r.TNF > TNFUnknown {
+			return nil, fmt.Errorf("ndef: record %d: invalid type name format %d", i, r.TNF)
+		}
+		if r.TNF == TNFEmpty && (len(r.Type) > 0 || len(r.ID) > 0 || len(r.Payload) > 0) {
+			return nil, fmt.Errorf("ndef: record %d: empty records must have no type, id or payload", i)
+		}
+		if len(r.Type) > 0xff || len(r.ID) > 0xff {
+			return nil, fmt.Errorf("ndef: record %d: type and id are limited to 255 bytes", i)
+		}
+
+		header := byte(r.TNF)
+		if i == 0 {
+			header |= flagMB
+		}
+		if i == len(m)-1 {
+			header |= flagME
+		}
+		if len(r.Payload) <= 0xff {
+			header |= flagSR
+		}
+		if len(r.ID) > 0 {
+			header |= flagIL
+		}
+
+		out = append(out, header, byte(len(r.Type)))
+		if header&flagSR != 0 {
+			out = append(out, byte(len(r.Payload)))
+		} else {
+			var n [4]byte
+			binary.BigEndian.PutUint32(n[:], uint32(len(r.Payload)))
+			out = append(out, n[:]...)
+		}
+		if len(r.ID) > 0 {
+			out = append(out, byte(len(r.ID)))
+		}
+		out = append(out, r.Type...)
+		out = append(out, r.ID...)
+		out = append(out, r.Payload...)
+	}
+	return out, nil
+}
+
+// Parse decodes a raw NDEF message (without the tag TLV wrapper, see Unwrap), reassembling
+// chunked records.
+func Parse(data []byte) (Message, error) {
+	var msg Message
+	chunked := false
+	for pos := 0; ; {
+		if pos >= len(data) {
+			return nil, errors.New("ndef: message ends without a record marked as last")
+		}
+		header := data[pos]
+		if (pos == 0) != (header&flagMB != 0) {
+			return nil, fmt.Errorf("ndef: unexpected message begin flag at offset %d", pos)
+		}
+		pos++
+
+		lengthBytes := 4
+		if header&flagSR != 0 {
+			lengthBytes = 1
+		}
+		need := 1 + lengthBytes
+		if header&flagIL != 0 {
+			need++
+		}
+		if pos+need > len(data) {
+			return nil, errors.New("ndef: truncated record header")
+		}
+		typeLen := int(data[pos])
+		pos++
+		payloadLen := int(data[pos])
+		if lengthBytes == 4 {
+			n := binary.BigEndian.Uint32(data[pos:])
+			if uint64(n) > uint64(len(data)) {
+				return nil, errors.New("ndef: truncated record")
+			}
+			payloadLen = int(n)
+		}
+		pos += lengthBytes
+		idLen := 0
+		if header&flagIL != 0 {
+			idLen = int(data[pos])
+			pos++
+		}
+		if pos+typeLen+idLen+payloadLen > len(data) {
+			return nil, errors.New("ndef: truncated record")
+		}
+
+		typ := data[pos : pos+typeLen]
+		pos += typeLen
+		id := data[pos : pos+idLen]
+		pos += idLen
+		payload := data[pos : pos+payloadLen]
+		pos += payloadLen
+
+		tnf := TNF(header & tnfMask)
+		if chunked {
+			// middle and last chunks carry only payload
+			if tnf != TNFUnchanged || typeLen != 0 || idLen != 0 {
+				return nil, errors.New("ndef: invalid chunk continuation")
+			}
+			last := &msg[len(msg)-1]
+			last.Payload = append(last.Payload, payload...)
+		} else {
+			if tnf >= TNFUnchanged {
+				return nil, fmt.Errorf("ndef: invalid type name format %d", tnf)
+			}
+			msg = append(msg, Record{
+				TNF:     tnf,
+				Type:    append([]byte(nil), typ...),
+				ID:      append([]byte(nil), id...),
+				Payload: append([]byte(nil), payload...),
+			})
+		}
+		chunked = header&flagCF != 0
+
+		if header&flagME != 0 {
+			if chunked {
+				return nil, errors.New("ndef: message ends inside a chunked record")
+			}
+			return msg, nil
+		}
+	}
+}
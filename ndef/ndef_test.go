@@ -0,0 +1,122 @@
+package ndef
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMarshalKnownRecords(t *testing.T) {
+	text, err := NewTextRecord("hello", "en")
+	if err != nil {
+		t.Fatalf("text record: %v", err)
+	}
+	tests := []struct {
+		name string
+		rec  Record
+		want []byte
+	}{
+		{"text", text, append([]byte{0xd1, 0x01, 0x08, 'T', 0x02, 'e', 'n'}, "hello"...)},
+		{"uri", NewURIRecord("https://www.example.com"), append([]byte{0xd1, 0x01, 0x0c, 'U', 0x02}, "example.com"...)},
+		{"uri without prefix", NewURIRecord("pix:0002"), append([]byte{0xd1, 0x01, 0x09, 'U', 0x00}, "pix:0002"...)},
+	}
+	for _, tt := range tests {
+		got, err := Message{tt.rec}.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", tt.name, err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Fatalf("%s: expected % x, got % x", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	text, err := NewTextRecord("Pagamento às 10h", "pt-BR")
+	if err != nil {
+		t.Fatalf("text record: %v", err)
+	}
+	ext, err := NewExternalRecord("Example.com:Pix", bytes.Repeat([]byte("x"), 300))
+	if err != nil {
+		t.Fatalf("external record: %v", err)
+	}
+	ext.ID = []byte("1")
+	msg := Message{text, NewURIRecord("tel:+5511999999999"), ext}
+
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	// the 300 byte payload needs a long record: four byte length and an ID length
+	if i := bytes.Index(data, []byte("example.com:pix")); i < 0 || data[i-7]&(flagSR|flagIL) != flagIL {
+		t.Fatalf("expected a long external record with id, got % x", data)
+	}
+
+	got, err := Parse(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(got))
+	}
+	if s, lang, err := got[0].Text(); err != nil || s != "Pagamento às 10h" || lang != "pt-BR" {
+		t.Fatalf("unexpected text %q %q %v", s, lang, err)
+	}
+	if uri, err := got[1].URI(); err != nil || uri != "tel:+5511999999999" {
+		t.Fatalf("unexpected uri %q %v", uri, err)
+	}
+	if got[2].TNF != TNFExternal || string(got[2].Type) != "example.com:pix" || string(got[2].ID) != "1" || len(got[2].Payload) != 300 {
+		t.Fatalf("unexpected external record %+v", got[2])
+	}
+}
+
+func TestParseChunked(t *testing.T) {
+	data := []byte{
+		0x80 | flagCF | flagSR | byte(TNFMedia), 0x0a, 0x03, // first chunk carries the type
+	}
+	data = append(data, "text/plain"...)
+	data = append(data, "000"...)
+	data = append(data, flagCF|flagSR|byte(TNFUnchanged), 0x00, 0x02, '2', '0')
+	data = append(data, flagME|flagSR|byte(TNFUnchanged), 0x00, 0x01, '1')
+
+	msg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(msg) != 1 || msg[0].TNF != TNFMedia || string(msg[0].Type) != "text/plain" || string(msg[0].Payload) != "000201" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	valid, _ := Message{NewURIRecord("https://example.com")}.MarshalBinary()
+	tests := map[string][]byte{
+		"empty":            nil,
+		"truncated":        valid[:len(valid)-1],
+		"no begin flag":    append([]byte{valid[0] &^ flagMB}, valid[1:]...),
+		"no end flag":      append([]byte{valid[0] &^ flagME}, valid[1:]...),
+		"long length":      {0xc1, 0x01, 0xff, 0xff, 0xff, 0xff, 'U'},
+		"orphan chunk":     {0xd6, 0x00, 0x00},
+		"unfinished chunk": {0xf1, 0x01, 0x00, 'U'},
+	}
+	for name, data := range tests {
+		if _, err := Parse(data); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+
+	if _, err := NewTextRecord("x", strings.Repeat("a", 64)); err == nil {
+		t.Fatalf("expected long languages to be rejected")
+	}
+	for _, typ := range []string{"pix", ":pix", "example.com:", "exa mple.com:pix"} {
+		if _, err := NewExternalRecord(typ, nil); err == nil {
+			t.Fatalf("expected external type %q to be rejected", typ)
+		}
+	}
+	if _, err := (Message{}).MarshalBinary(); err == nil {
+		t.Fatalf("expected empty messages to be rejected")
+	}
+	if _, err := (Message{{TNF: TNFEmpty, Payload: []byte("x")}}).MarshalBinary(); err == nil {
+		t.Fatalf("expected empty records with a payload to be rejected")
+	}
+}
@@ -0,0 +1,162 @@
+package ndef
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/thiagozs/go-pixgen/pix"
+)
+
+const (
+	// DefaultLang is the language of Pix text records.
+	DefaultLang = "pt-BR"
+
+	// payloadStart is the payload format indicator every Pix payload begins with.
+	payloadStart = "000201"
+)
+
+// RecordKind selects the record carrying the Pix payload.
+type RecordKind int
+
+const (
+	// KindText stores the copia-e-cola in a text record, shown by any NFC reader app.
+	KindText RecordKind = iota
+	// KindURI stores the escaped payload after PixOptions.URIPrefix, opening the app
+	// registered for it.
+	KindURI
+	// KindExternal stores the raw payload in a record of type PixOptions.ExternalType.
+	KindExternal
+)
+
+// PixOptions configures NewPixMessage. The zero value builds a pt-BR text record.
+type PixOptions struct {
+	Kind RecordKind
+	// Lang is the text record language (default DefaultLang).
+	Lang string
+	// URIPrefix is the URI record prefix, required for KindURI: a link to a checkout you
+	// own, such as "https://pay.example.com/pix?c=", or a scheme your app registers.
+	URIPrefix string
+	// ExternalType is the external record type, required for KindExternal: a domain you
+	// own and a name, such as "example.com:pix". Android dispatches it as
+	// vnd.android.nfc://ext/example.com:pix.
+	ExternalType string
+}
+
+// NewPixMessage validates a Pix payload and wraps it in a single record message.
+func NewPixMessage(payload string, opts PixOptions) (Message, error) {
+	if _, err := pix.ParsePayload(payload); err != nil {
+		return nil, fmt.Errorf("ndef: %w", err)
+	}
+
+	var rec Record
+	var err error
+	switch opts.Kind {
+	case KindText:
+		lang := opts.Lang
+		if lang == "" {
+			lang = DefaultLang
+		}
+		rec, err = NewTextRecord(payload, lang)
+	case KindURI:
+		if opts.URIPrefix == "" {
+			return nil, errors.New("ndef: URIPrefix is required for URI records")
+		}
+		rec = NewURIRecord(opts.URIPrefix + url.QueryEscape(payload))
+	case KindExternal:
+		if opts.ExternalType == "" {
+			return nil, errors.New("ndef: ExternalType is required for external records")
+		}
+		rec, err = NewExternalRecord(opts.ExternalType, []byte(payload))
+	default:
+		return nil, fmt.Errorf("ndef: unknown record kind %d", opts.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Message{rec}, nil
+}
+
+// Encode builds the message of NewPixMessage and returns its raw bytes.
+func Encode(payload string, opts PixOptions) ([]byte, error) {
+	msg, err := NewPixMessage(payload, opts)
+	if err != nil {
+		return nil, err
+	}
+	return msg.MarshalBinary()
+}
+
+// ParsePix reads a raw NDEF message or a tag memory image (NDEF TLV) and parses the Pix
+// payloads of its text, URI, external and text/plain records, in order. Records that do
+// not hold a valid Pix payload are skipped; an error is returned when none does.
+func ParsePix(data []byte) ([]*pix.ParsedPayload, error) {
+	// a message starts with a record header with the begin flag set, a tag image with a TLV
+	if len(data) > 0 && data[0]&flagMB == 0 {
+		var err error
+		if data, err = Unwrap(data); err != nil {
+			return nil, err
+		}
+	}
+	msg, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed []*pix.ParsedPayload
+	var firstErr error
+	for _, rec := range msg {
+		p, err := pix.ParsePayload(recordPayload(rec))
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		parsed = append(parsed, p)
+	}
+	if len(parsed) == 0 {
+		if firstErr == nil {
+			firstErr = errors.New("no records")
+		}
+		return nil, fmt.Errorf("ndef: no pix payload among %d record(s): %w", len(msg), firstErr)
+	}
+	return parsed, nil
+}
+
+// recordPayload extracts the candidate Pix payload of a record.
+func recordPayload(rec Record) string {
+	switch {
+	case rec.TNF == TNFWellKnown && string(rec.Type) == string(TypeText):
+		text, _, _ := rec.Text()
+		return strings.TrimSpace(text)
+	case rec.TNF == TNFWellKnown && string(rec.Type) == string(TypeURI):
+		uri, _ := rec.URI()
+		return uriPayload(uri)
+	case rec.TNF == TNFAbsoluteURI:
+		return uriPayload(string(rec.Type))
+	}
+	return strings.TrimSpace(string(rec.Payload))
+}
+
+// uriPayload finds the payload in a query value ("...?c=000201...") or at the end of the
+// URI ("myapp:000201...").
+func uriPayload(uri string) string {
+	if u, err := url.Parse(uri); err == nil {
+		for _, values := range u.Query() {
+			for _, v := range values {
+				if strings.HasPrefix(v, payloadStart) {
+					return v
+				}
+			}
+		}
+	}
+	i := strings.Index(uri, payloadStart)
+	if i < 0 {
+		return ""
+	}
+	if s, err := url.QueryUnescape(uri[i:]); err == nil {
+		return s
+	}
+	return uri[i:]
+}
@@ -0,0 +1,82 @@
+package ndef
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const samplePayload = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestEncodeParsePix(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   PixOptions
+		marker string
+	}{
+		{"text", PixOptions{}, "pt-BR000201"},
+		{"app uri", PixOptions{Kind: KindURI, URIPrefix: "myapp:"}, "myapp:000201"},
+		{"https uri", PixOptions{Kind: KindURI, URIPrefix: "https://pay.example.com/pix?c="}, "pay.example.com/pix?c=000201"},
+		{"external", PixOptions{Kind: KindExternal, ExternalType: "example.com:pix"}, "example.com:pix000201"},
+	}
+	for _, tt := range tests {
+		data, err := Encode(samplePayload, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: encode: %v", tt.name, err)
+		}
+		if !bytes.Contains(data, []byte(tt.marker)) {
+			t.Fatalf("%s: expected %q in % x", tt.name, tt.marker, data)
+		}
+
+		wrapped, err := Wrap(data)
+		if err != nil {
+			t.Fatalf("%s: wrap: %v", tt.name, err)
+		}
+		for _, input := range [][]byte{data, wrapped} {
+			parsed, err := ParsePix(input)
+			if err != nil {
+				t.Fatalf("%s: parse: %v", tt.name, err)
+			}
+			if len(parsed) != 1 || parsed[0].Raw != samplePayload || parsed[0].MerchantName != "Fulano de Tal" {
+				t.Fatalf("%s: unexpected payloads %+v", tt.name, parsed)
+			}
+		}
+	}
+}
+
+func TestPixTagCapacity(t *testing.T) {
+	msg, err := NewPixMessage(samplePayload, PixOptions{})
+	if err != nil {
+		t.Fatalf("message: %v", err)
+	}
+	// a static payload with a random key is already too long for the smallest sticker
+	if err := NTAG213.Check(msg); err == nil || !strings.Contains(err.Error(), "NTAG213") {
+		t.Fatalf("expected the payload not to fit an NTAG213, got %v", err)
+	}
+	if err := NTAG215.Check(msg); err != nil {
+		t.Fatalf("expected the payload to fit an NTAG215: %v", err)
+	}
+}
+
+func TestParsePixErrors(t *testing.T) {
+	if _, err := NewPixMessage("000201invalid", PixOptions{}); err == nil {
+		t.Fatalf("expected invalid payloads to be rejected")
+	}
+	if _, err := NewPixMessage(samplePayload, PixOptions{Kind: RecordKind(9)}); err == nil {
+		t.Fatalf("expected unknown record kinds to be rejected")
+	}
+	if _, err := NewPixMessage(samplePayload, PixOptions{Kind: KindURI}); err == nil || !strings.Contains(err.Error(), "URIPrefix") {
+		t.Fatalf("expected a URI record without prefix to be rejected, got %v", err)
+	}
+	if _, err := NewPixMessage(samplePayload, PixOptions{Kind: KindExternal}); err == nil || !strings.Contains(err.Error(), "ExternalType") {
+		t.Fatalf("expected an external record without type to be rejected, got %v", err)
+	}
+
+	link, _ := Message{NewURIRecord("https://example.com")}.MarshalBinary()
+	if _, err := ParsePix(link); err == nil {
+		t.Fatalf("expected a message without pix payloads to be rejected")
+	}
+	if _, err := ParsePix([]byte{0x00, 0xfe}); err == nil {
+		t.Fatalf("expected a blank tag to be rejected")
+	}
+}
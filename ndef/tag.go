@@ -0,0 +1,96 @@
+package ndef
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Type 2 tag TLV blocks (NFC Forum Type 2 Tag specification, section 2.3)
+const (
+	tlvNull       = 0x00
+	tlvNDEF       = 0x03
+	tlvTerminator = 0xfe
+)
+
+// Tag describes an NFC Forum Type 2 tag by the bytes available to the NDEF TLV.
+type Tag struct {
+	Name string
+	// Capacity is the user memory in bytes, after the capability container.
+	Capacity int
+}
+
+// NXP NTAG21x stickers, the usual choice for tap-to-pay.
+var (
+	NTAG213 = Tag{Name: "NTAG213", Capacity: 144}
+	NTAG215 = Tag{Name: "NTAG215", Capacity: 504}
+	NTAG216 = Tag{Name: "NTAG216", Capacity: 888}
+)
+
+// Wrap returns the tag memory image of an encoded message: the NDEF TLV (one byte length
+// up to 254 bytes, three bytes above) followed by the terminator TLV. Write it from the
+// first user page (page 4 on NTAG21x).
+func Wrap(message []byte) ([]byte, error) {
+	if len(message) > 0xfffe {
+		return nil, errors.New("ndef: message exceeds the 65534 bytes of a TLV")
+	}
+	out := make([]byte, 0, len(message)+5)
+	out = append(out, tlvNDEF)
+	if len(message) < 0xff {
+		out = append(out, byte(len(message)))
+	} else {
+		out = append(out, 0xff, byte(len(message)>>8), byte(len(message)))
+	}
+	out = append(out, message...)
+	return append(out, tlvTerminator), nil
+}
+
+// Unwrap returns the first NDEF message found in a tag memory image, skipping NULL, lock
+// control, memory control and proprietary TLVs.
+func Unwrap(data []byte) ([]byte, error) {
+	for pos := 0; pos < len(data); {
+		typ := data[pos]
+		pos++
+		switch typ {
+		case tlvNull:
+			continue
+		case tlvTerminator:
+			return nil, errors.New("ndef: no ndef message before the terminator tlv")
+		}
+		if pos >= len(data) {
+			break
+		}
+		n := int(data[pos])
+		pos++
+		if n == 0xff {
+			if pos+2 > len(data) {
+				break
+			}
+			n = int(data[pos])<<8 | int(data[pos+1])
+			pos += 2
+		}
+		if pos+n > len(data) {
+			return nil, fmt.Errorf("ndef: tlv 0x%02x exceeds the tag memory", typ)
+		}
+		if typ == tlvNDEF {
+			return data[pos : pos+n], nil
+		}
+		pos += n
+	}
+	return nil, errors.New("ndef: no ndef message tlv found")
+}
+
+// Check reports whether the message, once wrapped by Wrap, fits the tag.
+func (t Tag) Check(m Message) error {
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	wrapped, err := Wrap(data)
+	if err != nil {
+		return err
+	}
+	if len(wrapped) > t.Capacity {
+		return fmt.Errorf("ndef: %d bytes do not fit the %d bytes of an %s", len(wrapped), t.Capacity, t.Name)
+	}
+	return nil
+}
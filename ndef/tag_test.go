@@ -0,0 +1,61 @@
+package ndef
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrapUnwrap(t *testing.T) {
+	short := []byte{0xd1, 0x01, 0x01, 'U', 0x00}
+	wrapped, err := Wrap(short)
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+	if want := append([]byte{0x03, 0x05}, append(short, 0xfe)...); !bytes.Equal(wrapped, want) {
+		t.Fatalf("expected % x, got % x", want, wrapped)
+	}
+
+	long := bytes.Repeat([]byte{0xaa}, 300)
+	wrapped, err = Wrap(long)
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+	if !bytes.Equal(wrapped[:4], []byte{0x03, 0xff, 0x01, 0x2c}) || len(wrapped) != 305 {
+		t.Fatalf("unexpected three byte length tlv % x", wrapped[:4])
+	}
+
+	// NULL, lock control and memory control TLVs may precede the message
+	memory := append([]byte{0x00, 0x01, 0x03, 0xa0, 0x10, 0x44, 0x02, 0x03, 0x00, 0x00, 0x00}, wrapped...)
+	got, err := Unwrap(append(memory, 0x00, 0x00))
+	if err != nil {
+		t.Fatalf("unwrap: %v", err)
+	}
+	if !bytes.Equal(got, long) {
+		t.Fatalf("unwrapped message does not match")
+	}
+
+	for name, data := range map[string][]byte{
+		"terminator": {0x00, 0xfe, 0x03, 0x01, 0x00},
+		"truncated":  {0x03, 0x10, 0xd1},
+		"blank":      {0x00, 0x00, 0x00},
+	} {
+		if _, err := Unwrap(data); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestTagCheck(t *testing.T) {
+	msg := Message{{TNF: TNFExternal, Type: []byte("example.com:x"), Payload: bytes.Repeat([]byte("x"), 126)}}
+	// 3 byte header + 13 byte type + 126 byte payload, wrapped in 2 + 1 TLV bytes
+	if err := NTAG213.Check(msg); err == nil || err.Error() != "ndef: 145 bytes do not fit the 144 bytes of an NTAG213" {
+		t.Fatalf("unexpected NTAG213 result %v", err)
+	}
+	msg[0].Payload = msg[0].Payload[:125]
+	if err := NTAG213.Check(msg); err != nil {
+		t.Fatalf("expected 144 bytes to fit an NTAG213: %v", err)
+	}
+	if err := NTAG215.Check(Message{}); err == nil {
+		t.Fatalf("expected empty messages to be rejected")
+	}
+}
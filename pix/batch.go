@@ -0,0 +1,106 @@
+package pix
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Request describes a single item of a batch generation.
+type Request struct {
+	// ID is an opaque correlation identifier copied to the matching Result.
+	ID string
+	// Options configures the Pix exactly as in New.
+	Options []Options
+}
+
+// BatchOptions tunes GenerateBatch.
+type BatchOptions struct {
+	// Workers bounds the number of concurrent generations (default runtime.NumCPU()).
+	Workers int
+	// SkipQRCode disables PNG rendering, producing payloads only.
+	SkipQRCode bool
+}
+
+// Result carries the outcome of a batch item. Err is set when the item failed; the
+// remaining items are processed regardless.
+type Result struct {
+	ID      string
+	Index   int
+	Payload string
+	QRCode  []byte
+	Err     error
+}
+
+// GenerateBatch generates payloads and PNG QR codes for reqs using a bounded worker pool.
+// Results are delivered as they complete (not necessarily in input order) and the channel is
+// closed once every item has been processed. When ctx is cancelled no new items are started
+// and results not yet delivered are dropped.
+func GenerateBatch(ctx context.Context, reqs []Request, opts BatchOptions) <-chan Result {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(reqs) {
+		workers = len(reqs)
+	}
+
+	results := make(chan Result, workers)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res := generateBatchItem(reqs[i], opts)
+				res.Index = i
+				select {
+				case results <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(results)
+		defer wg.Wait()
+		defer close(jobs)
+		for i := range reqs {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+func generateBatchItem(req Request, opts BatchOptions) Result {
+	res := Result{ID: req.ID}
+
+	p, err := New(req.Options...)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	res.Payload, err = p.GenPayload()
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	if !opts.SkipQRCode {
+		res.QRCode, err = p.GenQRCode()
+		if err != nil {
+			res.Err = err
+		}
+	}
+	return res
+}
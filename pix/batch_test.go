@@ -0,0 +1,84 @@
+package pix
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+func batchRequests(n int) []Request {
+	reqs := make([]Request, n)
+	for i := range reqs {
+		reqs[i] = Request{
+			ID: fmt.Sprintf("invoice-%d", i),
+			Options: []Options{
+				OptPixKey("11999887766"),
+				OptMerchantName("FULANO DE TAL"),
+				OptMerchantCity("SAO PAULO"),
+				OptAmount(fmt.Sprintf("%d.00", i+1)),
+				OptTxId(fmt.Sprintf("INV%04d", i)),
+			},
+		}
+	}
+	return reqs
+}
+
+func TestGenerateBatch(t *testing.T) {
+	reqs := batchRequests(20)
+	reqs[7].Options = append(reqs[7].Options, OptAmount("invalid"))
+
+	seen := make(map[string]Result)
+	for res := range GenerateBatch(context.Background(), reqs, BatchOptions{Workers: 4}) {
+		if _, dup := seen[res.ID]; dup {
+			t.Fatalf("duplicated result for %s", res.ID)
+		}
+		seen[res.ID] = res
+	}
+
+	if len(seen) != len(reqs) {
+		t.Fatalf("expected %d results, got %d", len(reqs), len(seen))
+	}
+
+	for i, req := range reqs {
+		res := seen[req.ID]
+		if res.Index != i {
+			t.Fatalf("expected index %d for %s, got %d", i, req.ID, res.Index)
+		}
+		if i == 7 {
+			if res.Err == nil {
+				t.Fatalf("expected error for invalid amount")
+			}
+			continue
+		}
+		if res.Err != nil {
+			t.Fatalf("unexpected error for %s: %v", req.ID, res.Err)
+		}
+		parsed, err := ParsePayload(res.Payload)
+		if err != nil {
+			t.Fatalf("parse payload: %v", err)
+		}
+		if want := fmt.Sprintf("INV%04d", i); parsed.AdditionalDataField.TxID != want {
+			t.Fatalf("expected txid %s, got %s", want, parsed.AdditionalDataField.TxID)
+		}
+		if !bytes.HasPrefix(res.QRCode, []byte("\x89PNG")) {
+			t.Fatalf("expected PNG QR code for %s", req.ID)
+		}
+	}
+}
+
+func TestGenerateBatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results := GenerateBatch(ctx, batchRequests(200), BatchOptions{Workers: 2, SkipQRCode: true})
+	<-results
+	cancel()
+
+	count := 1
+	for range results {
+		count++
+	}
+	if count == 200 {
+		t.Fatalf("expected cancellation to stop the batch early")
+	}
+}
@@ -0,0 +1,356 @@
+package pix
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+const (
+	// DefaultCardWidth is the card width in pixels used when CardOptions.Width is zero.
+	DefaultCardWidth = 480
+	// DefaultCardPadding is the card padding in pixels used when CardOptions.Padding is zero.
+	DefaultCardPadding = 32
+
+	cardCopyPasteLabel = "Pix Copia e Cola"
+	cardMinWidth       = 240
+)
+
+// CardOptions configures the payment card renderers. Text is laid out with the Go fonts
+// embedded in the binary, so cards look the same on every host.
+type CardOptions struct {
+	// Width is the card width in pixels (default DefaultCardWidth); the height follows
+	// from the content.
+	Width int
+	// Padding is the margin around the content in pixels (default DefaultCardPadding).
+	Padding int
+	// ExpiresAt adds an expiry line below the copia-e-cola text when set.
+	ExpiresAt *time.Time
+	// Level and Logo configure the QR Code as in qrcode.QRCodeOptions.
+	Level qrcode.ErrorCorrectionLevel
+	Logo  *qrcode.Logo
+}
+
+type cardFontStyle int
+
+const (
+	cardRegular cardFontStyle = iota
+	cardBold
+	cardMono
+)
+
+var (
+	cardInk    = color.NRGBA{R: 0x1f, G: 0x29, B: 0x33, A: 0xff}
+	cardMuted  = color.NRGBA{R: 0x61, G: 0x6e, B: 0x7c, A: 0xff}
+	cardAccent = color.NRGBA{R: 0x00, G: 0x85, B: 0x77, A: 0xff}
+)
+
+// cardText is a single line of text positioned on the card. y is the baseline.
+type cardText struct {
+	text  string
+	style cardFontStyle
+	size  float64
+	color color.NRGBA
+	x, y  float64
+	width float64
+}
+
+type cardLayout struct {
+	width, height int
+	qrX, qrY      int
+	qrSide        int
+	texts         []cardText
+}
+
+var cardFonts struct {
+	once  sync.Once
+	fonts [3]*opentype.Font
+	err   error
+}
+
+func loadCardFonts() ([3]*opentype.Font, error) {
+	cardFonts.once.Do(func() {
+		for i, ttf := range [][]byte{goregular.TTF, gobold.TTF, gomono.TTF} {
+			f, err := opentype.Parse(ttf)
+			if err != nil {
+				cardFonts.err = fmt.Errorf("pix: load card font: %w", err)
+				return
+			}
+			cardFonts.fonts[i] = f
+		}
+	})
+	return cardFonts.fonts, cardFonts.err
+}
+
+func cardFace(style cardFontStyle, size float64) (font.Face, error) {
+	fonts, err := loadCardFonts()
+	if err != nil {
+		return nil, err
+	}
+	return opentype.NewFace(fonts[style], &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingNone})
+}
+
+func fixedToFloat(v fixed.Int26_6) float64 { return float64(v) / 64 }
+
+// layoutCard stacks merchant name, city, amount, QR Code, the wrapped copia-e-cola and the
+// optional expiry line, centered horizontally.
+func layoutCard(parsed *ParsedPayload, opts CardOptions) (cardLayout, error) {
+	if parsed == nil || parsed.Raw == "" {
+		return cardLayout{}, errors.New("pix: card requires a parsed payload")
+	}
+	width := opts.Width
+	if width == 0 {
+		width = DefaultCardWidth
+	}
+	padding := opts.Padding
+	if padding == 0 {
+		padding = DefaultCardPadding
+	}
+	if width < cardMinWidth {
+		return cardLayout{}, fmt.Errorf("pix: card width must be at least %d", cardMinWidth)
+	}
+	if padding < 0 || width-2*padding < cardMinWidth/2 {
+		return cardLayout{}, errors.New("pix: card padding leaves no room for the content")
+	}
+
+	l := cardLayout{width: width}
+	inner := float64(width - 2*padding)
+	w := float64(width)
+	y := float64(padding)
+
+	addLine := func(text string, style cardFontStyle, size float64, c color.NRGBA) error {
+		face, err := cardFace(style, size)
+		if err != nil {
+			return err
+		}
+		defer face.Close()
+		m := face.Metrics()
+		tw := fixedToFloat(font.MeasureString(face, text))
+		l.texts = append(l.texts, cardText{
+			text: text, style: style, size: size, color: c,
+			x: (w - tw) / 2, y: y + fixedToFloat(m.Ascent), width: tw,
+		})
+		y += fixedToFloat(m.Height) * 1.2
+		return nil
+	}
+
+	if parsed.MerchantName != "" {
+		if err := addLine(parsed.MerchantName, cardBold, w/18, cardInk); err != nil {
+			return cardLayout{}, err
+		}
+	}
+	if parsed.MerchantCity != "" {
+		if err := addLine(parsed.MerchantCity, cardRegular, w/30, cardMuted); err != nil {
+			return cardLayout{}, err
+		}
+	}
+	if parsed.TransactionAmount != "" {
+		if err := addLine(formatBRL(parsed.TransactionAmount), cardBold, w/12, cardAccent); err != nil {
+			return cardLayout{}, err
+		}
+	}
+
+	y += float64(padding) / 2
+	l.qrSide = int(inner)
+	l.qrX = padding
+	l.qrY = int(math.Ceil(y))
+	y = float64(l.qrY+l.qrSide) + float64(padding)/2
+
+	if err := addLine(cardCopyPasteLabel, cardBold, w/28, cardInk); err != nil {
+		return cardLayout{}, err
+	}
+	monoSize := w / 34
+	mono, err := cardFace(cardMono, monoSize)
+	if err != nil {
+		return cardLayout{}, err
+	}
+	advance, _ := mono.GlyphAdvance('0')
+	mono.Close()
+	perLine := int(inner / fixedToFloat(advance))
+	for _, line := range wrapFixed(parsed.Raw, perLine) {
+		if err := addLine(line, cardMono, monoSize, cardInk); err != nil {
+			return cardLayout{}, err
+		}
+	}
+
+	if opts.ExpiresAt != nil {
+		y += float64(padding) / 4
+		if err := addLine("Expira em "+opts.ExpiresAt.Format("02/01/2006 15:04"), cardRegular, w/30, cardMuted); err != nil {
+			return cardLayout{}, err
+		}
+	}
+
+	l.height = int(math.Ceil(y)) + padding
+	return l, nil
+}
+
+// wrapFixed splits s into chunks of at most n runes.
+func wrapFixed(s string, n int) []string {
+	if n < 1 {
+		n = 1
+	}
+	runes := []rune(s)
+	var lines []string
+	for len(runes) > n {
+		lines = append(lines, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return append(lines, string(runes))
+}
+
+// formatBRL formats a payload amount ("1234.5") as Brazilian currency ("R$ 1.234,50").
+func formatBRL(amount string) string {
+	intPart, frac := amount, ""
+	if i := strings.IndexByte(amount, '.'); i >= 0 {
+		intPart, frac = amount[:i], amount[i+1:]
+	}
+	intPart = strings.TrimLeft(intPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+
+	var b strings.Builder
+	b.WriteString("R$ ")
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte(',')
+	b.WriteString(frac)
+	return b.String()
+}
+
+// NewCardPNG renders a payment card for parsed as a PNG: the QR Code with the merchant
+// name, formatted amount, the wrapped copia-e-cola text and an optional expiry line.
+func NewCardPNG(parsed *ParsedPayload, opts CardOptions) ([]byte, error) {
+	l, err := layoutCard(parsed, opts)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := qrcode.New(qrcode.QRCodeOptions{
+		Content: parsed.Raw,
+		Size:    l.qrSide,
+		Level:   opts.Level,
+		Logo:    opts.Logo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	qrImg, err := png.Decode(bytes.NewReader(qr))
+	if err != nil {
+		return nil, fmt.Errorf("pix: decode qrcode: %w", err)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, l.width, l.height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	// the QR side is rounded down to whole pixels per module; center it in its slot
+	side := qrImg.Bounds().Dx()
+	qx, qy := l.qrX+(l.qrSide-side)/2, l.qrY+(l.qrSide-side)/2
+	draw.Draw(img, image.Rect(qx, qy, qx+side, qy+side), qrImg, image.Point{}, draw.Src)
+
+	for _, t := range l.texts {
+		face, err := cardFace(t.style, t.size)
+		if err != nil {
+			return nil, err
+		}
+		d := font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(t.color),
+			Face: face,
+			Dot:  fixed.Point26_6{X: fixed.Int26_6(t.x * 64), Y: fixed.Int26_6(t.y * 64)},
+		}
+		d.DrawString(t.text)
+		face.Close()
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("pix: encode card: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+var svgSizeAttrs = regexp.MustCompile(`width="\d+" height="\d+"`)
+
+// NewCardSVG renders the same card as NewCardPNG as an SVG document. The QR Code stays
+// vector; text uses the Go font family names with generic fallbacks, and monospace lines
+// carry their measured length so wrapping matches the PNG.
+func NewCardSVG(parsed *ParsedPayload, opts CardOptions) ([]byte, error) {
+	l, err := layoutCard(parsed, opts)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := qrcode.NewSVG(qrcode.SVGOptions{Content: parsed.Raw, Level: opts.Level, Logo: opts.Logo})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		l.width, l.height, l.width, l.height)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#ffffff"/>`, l.width, l.height)
+
+	// nest the QR document, replacing its own size with the card slot
+	doc := string(qr)
+	doc = doc[strings.Index(doc, "<svg"):]
+	loc := svgSizeAttrs.FindStringIndex(doc)
+	if loc == nil {
+		return nil, errors.New("pix: unexpected qrcode svg")
+	}
+	fmt.Fprintf(&buf, `%sx="%d" y="%d" width="%d" height="%d"%s`,
+		doc[:loc[0]], l.qrX, l.qrY, l.qrSide, l.qrSide, strings.TrimRight(doc[loc[1]:], "\n"))
+
+	for _, t := range l.texts {
+		fmt.Fprintf(&buf, `<text x="%s" y="%s" text-anchor="middle" font-size="%s" fill="%s"`,
+			svgNum(t.x+t.width/2), svgNum(t.y), svgNum(t.size), hexColor(t.color))
+		switch t.style {
+		case cardBold:
+			buf.WriteString(` font-family="Go, Helvetica, Arial, sans-serif" font-weight="bold"`)
+		case cardMono:
+			fmt.Fprintf(&buf, ` font-family="Go Mono, Menlo, Consolas, monospace" xml:space="preserve" textLength="%s" lengthAdjust="spacingAndGlyphs"`, svgNum(t.width))
+		default:
+			buf.WriteString(` font-family="Go, Helvetica, Arial, sans-serif"`)
+		}
+		buf.WriteByte('>')
+		if err := xml.EscapeText(&buf, []byte(t.text)); err != nil {
+			return nil, err
+		}
+		buf.WriteString("</text>")
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes(), nil
+}
+
+func svgNum(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func hexColor(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,103 @@
+package pix
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatBRL(t *testing.T) {
+	cases := map[string]string{
+		"0.50":       "R$ 0,50",
+		"10":         "R$ 10,00",
+		"1234.5":     "R$ 1.234,50",
+		"1234567.89": "R$ 1.234.567,89",
+		"100.00":     "R$ 100,00",
+	}
+	for in, want := range cases {
+		if got := formatBRL(in); got != want {
+			t.Errorf("formatBRL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNewCardPNG(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+
+	data, err := NewCardPNG(parsed, CardOptions{Width: 360})
+	if err != nil {
+		t.Fatalf("render card: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode card: %v", err)
+	}
+	if img.Bounds().Dx() != 360 {
+		t.Fatalf("expected width 360, got %d", img.Bounds().Dx())
+	}
+
+	expires := time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC)
+	withExpiry, err := NewCardPNG(parsed, CardOptions{Width: 360, ExpiresAt: &expires})
+	if err != nil {
+		t.Fatalf("render card: %v", err)
+	}
+	tall, err := png.Decode(bytes.NewReader(withExpiry))
+	if err != nil {
+		t.Fatalf("decode card: %v", err)
+	}
+	if tall.Bounds().Dy() <= img.Bounds().Dy() {
+		t.Fatalf("expected expiry line to grow the card")
+	}
+
+	if _, err := NewCardPNG(parsed, CardOptions{Width: 100}); err == nil {
+		t.Fatalf("expected narrow card to be rejected")
+	}
+	if _, err := NewCardPNG(nil, CardOptions{}); err == nil {
+		t.Fatalf("expected missing payload to be rejected")
+	}
+}
+
+func TestNewCardSVG(t *testing.T) {
+	p, err := New(
+		OptPixKey("11999887766"),
+		OptMerchantName("FULANO & CIA"),
+		OptMerchantCity("SAO PAULO"),
+		OptAmount("1234.50"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating pix: %v", err)
+	}
+	payload, err := p.GenPayload()
+	if err != nil {
+		t.Fatalf("generate payload: %v", err)
+	}
+	parsed, err := ParsePayload(payload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+
+	expires := time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC)
+	data, err := NewCardSVG(parsed, CardOptions{ExpiresAt: &expires})
+	if err != nil {
+		t.Fatalf("render card: %v", err)
+	}
+	svg := string(data)
+	for _, want := range []string{"FULANO &amp; CIA", "R$ 1.234,50", "Pix Copia e Cola", "Expira em 31/01/2025 18:00", `viewBox="0 0 `} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("expected %q in card svg", want)
+		}
+	}
+	// the wrapped lines must add up to the whole copia-e-cola
+	var joined strings.Builder
+	for _, line := range strings.Split(svg, `lengthAdjust="spacingAndGlyphs">`)[1:] {
+		joined.WriteString(line[:strings.Index(line, "</text>")])
+	}
+	if joined.String() != strings.ReplaceAll(payload, "&", "&amp;") {
+		t.Fatalf("wrapped payload mismatch:\n%s\n%s", joined.String(), payload)
+	}
+}
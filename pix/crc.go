@@ -0,0 +1,71 @@
+package pix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// crc16Table holds the precomputed CRC-16/CCITT-FALSE values (poly 0x1021, init 0xFFFF)
+// used by the EMV payload checksum (tag 63).
+var crc16Table = func() [256]uint16 {
+	var table [256]uint16
+	for i := 0; i < 256; i++ {
+		crc := uint16(i) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+// crc16 computes the CRC-16/CCITT-FALSE checksum of data.
+func crc16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc = crc<<8 ^ crc16Table[byte(crc>>8)^b]
+	}
+	return crc
+}
+
+// appendCRCHex appends the four uppercase hexadecimal digits of crc to dst.
+func appendCRCHex(dst []byte, crc uint16) []byte {
+	const hexDigits = "0123456789ABCDEF"
+	return append(dst,
+		hexDigits[crc>>12&0xF],
+		hexDigits[crc>>8&0xF],
+		hexDigits[crc>>4&0xF],
+		hexDigits[crc&0xF],
+	)
+}
+
+// ComputeCRC returns the four-digit uppercase hexadecimal CRC-16/CCITT-FALSE checksum of a
+// payload. The input must include everything up to and including the CRC tag header "6304".
+func ComputeCRC(payloadWithoutCRC string) string {
+	return string(appendCRCHex(make([]byte, 0, 4), crc16([]byte(payloadWithoutCRC))))
+}
+
+// VerifyCRC checks that a complete payload ends with the CRC tag (63) and that its checksum
+// matches the content, without parsing the remaining EMV fields.
+func VerifyCRC(payload string) error {
+	payload = strings.TrimSpace(payload)
+	if len(payload) < 8 {
+		return errors.New("payload too short to contain a CRC")
+	}
+	crcHeader := TAG_CRC + "04"
+	if payload[len(payload)-8:len(payload)-4] != crcHeader {
+		return errors.New("payload missing CRC tag (63)")
+	}
+
+	expected := strings.ToUpper(payload[len(payload)-4:])
+	computed := ComputeCRC(payload[:len(payload)-4])
+	if expected != computed {
+		return fmt.Errorf("crc mismatch: expected %s got %s", expected, computed)
+	}
+	return nil
+}
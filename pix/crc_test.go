@@ -0,0 +1,57 @@
+package pix
+
+import (
+	"strings"
+	"testing"
+)
+
+const bacenSamplePayload = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestComputeCRC(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"123456789", "29B1"},
+		{"", "FFFF"},
+		{bacenSamplePayload[:len(bacenSamplePayload)-4], "1D3D"},
+	}
+
+	for _, tc := range tests {
+		if got := ComputeCRC(tc.input); got != tc.want {
+			t.Errorf("ComputeCRC(%q) = %s; want %s", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestVerifyCRC(t *testing.T) {
+	if err := VerifyCRC(bacenSamplePayload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := VerifyCRC("1D3D"); err == nil {
+		t.Fatalf("expected error for short payload")
+	}
+
+	lower := bacenSamplePayload[:len(bacenSamplePayload)-4] + "1d3d"
+	if err := VerifyCRC(lower); err != nil {
+		t.Fatalf("lowercase checksum should be accepted: %v", err)
+	}
+
+	tampered := strings.Replace(bacenSamplePayload, "BRASILIA", "BRASILIO", 1)
+	if err := VerifyCRC(tampered); err == nil || !strings.Contains(err.Error(), "crc mismatch") {
+		t.Fatalf("expected crc mismatch error, got %v", err)
+	}
+
+	missing := bacenSamplePayload[:len(bacenSamplePayload)-8] + "99041D3D"
+	if err := VerifyCRC(missing); err == nil {
+		t.Fatalf("expected missing crc tag error")
+	}
+}
+
+func BenchmarkComputeCRC(b *testing.B) {
+	input := bacenSamplePayload[:len(bacenSamplePayload)-4]
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ComputeCRC(input)
+	}
+}
@@ -0,0 +1,295 @@
+package pix
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+// DefaultEmailQRCodeSize is the QR Code image side in pixels used when EmailOptions.QRCodeSize
+// is zero.
+const DefaultEmailQRCodeSize = 320
+
+// EmailOptions configures the charge e-mail built by WriteEmail.
+type EmailOptions struct {
+	// From and To are RFC 5322 addresses such as "Loja <cobranca@loja.com.br>".
+	From    string
+	To      []string
+	ReplyTo string
+	// Subject defaults to "Cobrança Pix" followed by the merchant and the amount.
+	Subject string
+	// Date defaults to the current time.
+	Date time.Time
+	// HTMLTemplate and TextTemplate render the bodies with EmailData; the built-in
+	// Portuguese templates are used when nil.
+	HTMLTemplate *htmltemplate.Template
+	TextTemplate *texttemplate.Template
+	// Data is passed to the templates as EmailData.Data.
+	Data interface{}
+	// ExpiresAt is shown by the built-in templates when set.
+	ExpiresAt *time.Time
+	// QRCodeSize is the side of the inline PNG (default DefaultEmailQRCodeSize); Level and
+	// Logo configure the QR Code as in qrcode.QRCodeOptions.
+	QRCodeSize int
+	Level      qrcode.ErrorCorrectionLevel
+	Logo       *qrcode.Logo
+}
+
+// EmailData is the data the e-mail templates are executed with.
+type EmailData struct {
+	Parsed   *ParsedPayload
+	Merchant string
+	City     string
+	// Amount is formatted as "R$ 1.234,50", empty for open amounts.
+	Amount string
+	// TxID is empty for the "***" placeholder.
+	TxID string
+	// Payload is the copia-e-cola string.
+	Payload string
+	// QRCodeURL is the "cid:" URL of the inline QR Code image, for <img src>.
+	QRCodeURL htmltemplate.URL
+	// ExpiresAt is formatted as "02/01/2006 15:04", empty when unset.
+	ExpiresAt string
+	Data      interface{}
+}
+
+var defaultEmailHTML = htmltemplate.Must(htmltemplate.New("email.html").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head><meta charset="utf-8"><title>Cobrança Pix</title></head>
+<body style="margin:0;padding:24px;background:#f2f5f7;font-family:Helvetica,Arial,sans-serif;color:#1f2933">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0"><tr><td align="center">
+<table role="presentation" width="420" cellpadding="0" cellspacing="0" style="max-width:420px;background:#ffffff;border-radius:12px;padding:24px;text-align:center">
+<tr><td>
+{{if .Merchant}}<h1 style="margin:0;font-size:20px">{{.Merchant}}</h1>{{end}}
+{{if .City}}<p style="margin:2px 0 0;color:#616e7c">{{.City}}</p>{{end}}
+{{if .Amount}}<p style="margin:12px 0 0;font-size:28px;font-weight:bold;color:#008577">{{.Amount}}</p>{{end}}
+<p style="margin:16px 0"><img src="{{.QRCodeURL}}" width="240" height="240" alt="QR Code Pix" style="display:block;margin:0 auto;width:240px;height:240px"></p>
+<p style="margin:0 0 6px;font-weight:bold">Pix Copia e Cola</p>
+<p style="margin:0;padding:8px;background:#f9fafb;border:1px solid #cbd2d9;border-radius:8px;font-family:Menlo,Consolas,monospace;font-size:12px;word-break:break-all">{{.Payload}}</p>
+{{if .ExpiresAt}}<p style="margin:12px 0 0;font-size:13px;color:#616e7c">Expira em {{.ExpiresAt}}</p>{{end}}
+{{if .TxID}}<p style="margin:12px 0 0;font-size:13px;color:#616e7c">TxID: {{.TxID}}</p>{{end}}
+</td></tr>
+</table>
+</td></tr></table>
+</body>
+</html>
+`))
+
+var defaultEmailText = texttemplate.Must(texttemplate.New("email.txt").Parse(`Cobrança Pix{{if .Merchant}} - {{.Merchant}}{{end}}
+{{if .Amount}}
+Valor: {{.Amount}}
+{{- end}}
+{{- if .ExpiresAt}}
+Expira em: {{.ExpiresAt}}
+{{- end}}
+{{- if .TxID}}
+TxID: {{.TxID}}
+{{- end}}
+
+Pague lendo o QR Code no app do seu banco ou copie o código Pix Copia e Cola:
+
+{{.Payload}}
+`))
+
+// WriteEmail writes an RFC 5322 message for parsed to w: a multipart/related body holding
+// a multipart/alternative with the text and HTML versions, and the QR Code PNG attached
+// inline and referenced by its Content-ID. Text parts are quoted-printable UTF-8 with CRLF
+// line endings, ready for any SMTP client or an .eml file.
+func WriteEmail(w io.Writer, parsed *ParsedPayload, opts EmailOptions) error {
+	if parsed == nil || parsed.Raw == "" {
+		return errors.New("pix: email requires a parsed payload")
+	}
+	from, err := mail.ParseAddress(opts.From)
+	if err != nil {
+		return fmt.Errorf("pix: invalid from address: %w", err)
+	}
+	if len(opts.To) == 0 {
+		return errors.New("pix: email requires at least one recipient")
+	}
+	to := make([]string, len(opts.To))
+	for i, addr := range opts.To {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("pix: invalid to address %q: %w", addr, err)
+		}
+		to[i] = a.String()
+	}
+
+	size := opts.QRCodeSize
+	if size == 0 {
+		size = DefaultEmailQRCodeSize
+	}
+	png, err := qrcode.New(qrcode.QRCodeOptions{Content: parsed.Raw, Size: size, Level: opts.Level, Logo: opts.Logo})
+	if err != nil {
+		return err
+	}
+
+	token, err := emailToken()
+	if err != nil {
+		return err
+	}
+	domain := from.Address[strings.LastIndexByte(from.Address, '@')+1:]
+	cid := "qrcode." + token + "@" + domain
+
+	data := EmailData{
+		Parsed:    parsed,
+		Merchant:  parsed.MerchantName,
+		City:      parsed.MerchantCity,
+		Payload:   parsed.Raw,
+		QRCodeURL: htmltemplate.URL("cid:" + cid),
+		Data:      opts.Data,
+	}
+	if parsed.TransactionAmount != "" {
+		data.Amount = formatBRL(parsed.TransactionAmount)
+	}
+	if txid := parsed.AdditionalDataField.TxID; txid != "***" {
+		data.TxID = txid
+	}
+	if opts.ExpiresAt != nil {
+		data.ExpiresAt = opts.ExpiresAt.Format("02/01/2006 15:04")
+	}
+
+	htmlTmpl, textTmpl := opts.HTMLTemplate, opts.TextTemplate
+	if htmlTmpl == nil {
+		htmlTmpl = defaultEmailHTML
+	}
+	if textTmpl == nil {
+		textTmpl = defaultEmailText
+	}
+	var htmlBody, textBody bytes.Buffer
+	if err := htmlTmpl.Execute(&htmlBody, data); err != nil {
+		return fmt.Errorf("pix: render email html: %w", err)
+	}
+	if err := textTmpl.Execute(&textBody, data); err != nil {
+		return fmt.Errorf("pix: render email text: %w", err)
+	}
+
+	subject := opts.Subject
+	if subject == "" {
+		subject = strings.Join(nonEmpty("Cobrança Pix", data.Merchant, data.Amount), " - ")
+	}
+	date := opts.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	bw := bufio.NewWriter(w)
+	related := multipart.NewWriter(bw)
+	// the inner boundary goes in the header of the related part that holds it
+	innerBoundary := "alt-" + related.Boundary()
+
+	header := []string{
+		"From: " + from.String(),
+		"To: " + strings.Join(to, ", "),
+	}
+	if opts.ReplyTo != "" {
+		replyTo, err := mail.ParseAddress(opts.ReplyTo)
+		if err != nil {
+			return fmt.Errorf("pix: invalid reply-to address: %w", err)
+		}
+		header = append(header, "Reply-To: "+replyTo.String())
+	}
+	header = append(header,
+		"Subject: "+mime.QEncoding.Encode("utf-8", subject),
+		"Date: "+date.Format(time.RFC1123Z),
+		"Message-ID: <"+token+"@"+domain+">",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/related; type="multipart/alternative"; boundary="`+related.Boundary()+`"`,
+	)
+	for _, line := range header {
+		bw.WriteString(line + "\r\n")
+	}
+	bw.WriteString("\r\n")
+
+	part, err := related.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {`multipart/alternative; boundary="` + innerBoundary + `"`},
+	})
+	if err != nil {
+		return err
+	}
+	alternative := multipart.NewWriter(part)
+	if err := alternative.SetBoundary(innerBoundary); err != nil {
+		return err
+	}
+	if err := writeEmailText(alternative, "text/plain; charset=utf-8", textBody.Bytes()); err != nil {
+		return err
+	}
+	if err := writeEmailText(alternative, "text/html; charset=utf-8", htmlBody.Bytes()); err != nil {
+		return err
+	}
+	if err := alternative.Close(); err != nil {
+		return err
+	}
+
+	part, err = related.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {`image/png; name="pix-qrcode.png"`},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Id":                {"<" + cid + ">"},
+		"Content-Disposition":       {`inline; filename="pix-qrcode.png"`},
+	})
+	if err != nil {
+		return err
+	}
+	encoded := base64.StdEncoding.EncodeToString(png)
+	for len(encoded) > 76 {
+		io.WriteString(part, encoded[:76]+"\r\n")
+		encoded = encoded[76:]
+	}
+	io.WriteString(part, encoded+"\r\n")
+
+	if err := related.Close(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// writeEmailText adds a quoted-printable text part.
+func writeEmailText(mw *multipart.Writer, contentType string, body []byte) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write(body); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// emailToken returns a random identifier for the Message-ID and Content-ID headers.
+func emailToken() (string, error) {
+	var b [12]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("pix: email id: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+func nonEmpty(values ...string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
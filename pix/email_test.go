@@ -0,0 +1,176 @@
+package pix
+
+import (
+	"bytes"
+	"encoding/base64"
+	htmltemplate "html/template"
+	"image/png"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+func TestWriteEmail(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	expires := time.Date(2030, 1, 2, 15, 4, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err = WriteEmail(&buf, parsed, EmailOptions{
+		From:      "Padaria São José <cobranca@padaria.com.br>",
+		To:        []string{"cliente@example.com", "Outro <outro@example.com>"},
+		Date:      time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC),
+		ExpiresAt: &expires,
+	})
+	if err != nil {
+		t.Fatalf("write email: %v", err)
+	}
+	raw := buf.String()
+	if strings.Contains(strings.ReplaceAll(raw, "\r\n", ""), "\n") {
+		t.Fatalf("expected CRLF line endings only")
+	}
+
+	msg, err := mail.ReadMessage(&buf)
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	dec := new(mime.WordDecoder)
+	if subject, err := dec.DecodeHeader(msg.Header.Get("Subject")); err != nil || subject != "Cobrança Pix - Fulano de Tal" {
+		t.Fatalf("unexpected subject %q (%v)", subject, err)
+	}
+	if from, err := msg.Header.AddressList("From"); err != nil || from[0].Name != "Padaria São José" {
+		t.Fatalf("unexpected from %v (%v)", from, err)
+	}
+	if to, err := msg.Header.AddressList("To"); err != nil || len(to) != 2 {
+		t.Fatalf("unexpected to %v (%v)", to, err)
+	}
+	if date, err := msg.Header.Date(); err != nil || !date.Equal(time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected date %v (%v)", date, err)
+	}
+	if id := msg.Header.Get("Message-Id"); !strings.HasSuffix(id, "@padaria.com.br>") {
+		t.Fatalf("unexpected message id %q", id)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/related" || params["type"] != "multipart/alternative" {
+		t.Fatalf("unexpected content type %q", msg.Header.Get("Content-Type"))
+	}
+	related := multipart.NewReader(msg.Body, params["boundary"])
+
+	// first part: the text and HTML alternatives
+	part, err := related.NextPart()
+	if err != nil {
+		t.Fatalf("alternative part: %v", err)
+	}
+	_, altParams, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("alternative content type: %v", err)
+	}
+	bodies := map[string]string{}
+	alternative := multipart.NewReader(part, altParams["boundary"])
+	for {
+		p, err := alternative.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("alternative: %v", err)
+		}
+		if enc := p.Header.Get("Content-Transfer-Encoding"); enc != "quoted-printable" {
+			t.Fatalf("unexpected transfer encoding %q", enc)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(p))
+		if err != nil {
+			t.Fatalf("read body: %v", err)
+		}
+		mediaType, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		bodies[mediaType] = string(body)
+	}
+	for _, mediaType := range []string{"text/plain", "text/html"} {
+		if !strings.Contains(bodies[mediaType], bacenSamplePayload) || !strings.Contains(bodies[mediaType], "02/01/2030 15:04") {
+			t.Fatalf("expected the payload and expiry in the %s body:\n%s", mediaType, bodies[mediaType])
+		}
+	}
+
+	// second part: the inline QR Code referenced by the HTML body
+	part, err = related.NextPart()
+	if err != nil {
+		t.Fatalf("image part: %v", err)
+	}
+	cid := strings.Trim(part.Header.Get("Content-Id"), "<>")
+	if cid == "" || !strings.Contains(bodies["text/html"], `src="cid:`+cid+`"`) {
+		t.Fatalf("expected the html body to reference content id %q", cid)
+	}
+	if d := part.Header.Get("Content-Disposition"); !strings.HasPrefix(d, "inline") {
+		t.Fatalf("unexpected disposition %q", d)
+	}
+	img, err := png.Decode(base64.NewDecoder(base64.StdEncoding, part))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	codes, err := qrcode.Decode(img)
+	if err != nil || len(codes) != 1 || codes[0].Content != bacenSamplePayload {
+		t.Fatalf("unexpected qr codes %+v (%v)", codes, err)
+	}
+	if _, err := related.NextPart(); err != io.EOF {
+		t.Fatalf("expected two related parts, got %v", err)
+	}
+}
+
+func TestWriteEmailTemplates(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	html := htmltemplate.Must(htmltemplate.New("html").Parse(`<p>Olá {{.Data}}</p><img src="{{.QRCodeURL}}"><code>{{.Payload}}</code>`))
+	text := texttemplate.Must(texttemplate.New("text").Parse(`Olá {{.Data}}, pedido {{.Parsed.MerchantCity}}: {{.Payload}}`))
+
+	var buf bytes.Buffer
+	err = WriteEmail(&buf, parsed, EmailOptions{
+		From:         "cobranca@loja.com.br",
+		To:           []string{"cliente@example.com"},
+		ReplyTo:      "Suporte <suporte@loja.com.br>",
+		Subject:      "Seu pedido",
+		HTMLTemplate: html,
+		TextTemplate: text,
+		Data:         "<Maria>",
+	})
+	if err != nil {
+		t.Fatalf("write email: %v", err)
+	}
+	raw := buf.String()
+	for _, want := range []string{
+		"Subject: Seu pedido\r\n",
+		"Reply-To: \"Suporte\" <suporte@loja.com.br>\r\n",
+		"Ol=C3=A1 <Maria>, pedido BRASILIA",
+		"<p>Ol=C3=A1 &lt;Maria&gt;</p><img src=3D\"cid:qrcode.",
+	} {
+		if !strings.Contains(raw, want) {
+			t.Fatalf("expected %q in message:\n%s", want, raw)
+		}
+	}
+
+	for name, opts := range map[string]EmailOptions{
+		"from":     {From: "not an address", To: []string{"a@b.c"}},
+		"to":       {From: "a@b.c"},
+		"bad to":   {From: "a@b.c", To: []string{"nope"}},
+		"reply-to": {From: "a@b.c", To: []string{"a@b.c"}, ReplyTo: "nope"},
+		"template": {From: "a@b.c", To: []string{"a@b.c"}, TextTemplate: texttemplate.Must(texttemplate.New("t").Parse(`{{.Missing}}`))},
+	} {
+		if err := WriteEmail(io.Discard, parsed, opts); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+	if err := WriteEmail(io.Discard, nil, EmailOptions{From: "a@b.c", To: []string{"a@b.c"}}); err == nil {
+		t.Fatalf("expected a nil payload to be rejected")
+	}
+}
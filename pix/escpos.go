@@ -0,0 +1,178 @@
+package pix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+// DefaultEscPosPaperWidth is the roll width in millimeters used when EscPosOptions.PaperWidth
+// is zero.
+const DefaultEscPosPaperWidth = 80
+
+// escPosPapers maps the supported roll widths (mm) to the printable dots and the columns of
+// font A at 203 dpi.
+var escPosPapers = map[int]struct{ dots, columns int }{
+	58: {dots: 384, columns: 32},
+	80: {dots: 576, columns: 48},
+}
+
+// escPosRasterBand is the number of dot rows sent per raster command; small printers
+// reject larger images in a single command.
+const escPosRasterBand = 128
+
+// EscPosOptions configures the ESC/POS receipt renderer for thermal printers.
+type EscPosOptions struct {
+	// PaperWidth is the roll width in millimeters: 58 or 80 (default DefaultEscPosPaperWidth).
+	PaperWidth int
+	// ModuleSize is the side of a QR module in dots (1-16). When zero, the largest size
+	// keeping the code within two thirds of the paper width is used.
+	ModuleSize int
+	Level      qrcode.ErrorCorrectionLevel
+	// Raster prints the QR Code as a bit image (GS v 0) instead of the native QR command
+	// (GS ( k), for printers without QR support.
+	Raster bool
+	// NoCut leaves out the final feed and paper cut.
+	NoCut bool
+}
+
+// NewEscPos builds the ESC/POS byte stream of a Pix receipt: merchant, city, amount and
+// TxID header lines, the QR Code centered, then a partial cut. Write it to the printer
+// device (e.g. /dev/usb/lp0) or a spool file.
+func NewEscPos(parsed *ParsedPayload, opts EscPosOptions) ([]byte, error) {
+	if parsed == nil || parsed.Raw == "" {
+		return nil, errors.New("pix: escpos requires a parsed payload")
+	}
+	if opts.PaperWidth == 0 {
+		opts.PaperWidth = DefaultEscPosPaperWidth
+	}
+	paper, ok := escPosPapers[opts.PaperWidth]
+	if !ok {
+		return nil, fmt.Errorf("pix: unsupported paper width %dmm (expected 58 or 80)", opts.PaperWidth)
+	}
+	if opts.ModuleSize < 0 || opts.ModuleSize > 16 {
+		return nil, errors.New("pix: escpos module size must be between 1 and 16 dots")
+	}
+
+	m, err := qrcode.Encode(parsed.Raw, opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	module := opts.ModuleSize
+	if module == 0 {
+		module = paper.dots * 2 / 3 / m.Dimension()
+		if module > 16 {
+			module = 16
+		}
+		if module < 1 {
+			module = 1
+		}
+	}
+	if m.Dimension()*module > paper.dots {
+		return nil, fmt.Errorf("pix: qr code of %d dots does not fit the %dmm paper", m.Dimension()*module, opts.PaperWidth)
+	}
+
+	var buf bytes.Buffer
+	buf.Write([]byte{0x1b, 0x40})       // ESC @: initialize
+	buf.Write([]byte{0x1b, 0x61, 0x01}) // ESC a 1: center
+
+	line := func(text string) {
+		buf.WriteString(escPosText(text, paper.columns))
+		buf.WriteByte('\n')
+	}
+	buf.Write([]byte{0x1b, 0x45, 0x01}) // ESC E 1: bold
+	line(parsed.MerchantName)
+	buf.Write([]byte{0x1b, 0x45, 0x00})
+	line(parsed.MerchantCity)
+	if parsed.TransactionAmount != "" {
+		buf.Write([]byte{0x1d, 0x21, 0x11}) // GS ! 0x11: double width and height
+		buf.WriteString(escPosText(formatBRL(parsed.TransactionAmount), paper.columns/2))
+		buf.WriteByte('\n')
+		buf.Write([]byte{0x1d, 0x21, 0x00})
+	}
+	if txid := parsed.AdditionalDataField.TxID; txid != "" && txid != "***" {
+		line("TxID: " + txid)
+	}
+	buf.WriteByte('\n')
+
+	if opts.Raster {
+		writeEscPosRaster(&buf, m, module)
+	} else {
+		writeEscPosQR(&buf, parsed.Raw, opts.Level, module)
+	}
+
+	buf.WriteByte('\n')
+	buf.Write([]byte{0x1b, 0x61, 0x00}) // ESC a 0: back to left alignment
+	if !opts.NoCut {
+		buf.Write([]byte{0x1d, 0x56, 0x42, 0x03}) // GS V 66 3: feed 3 lines and partial cut
+	}
+	return buf.Bytes(), nil
+}
+
+// writeEscPosQR emits the native QR Code commands (GS ( k, functions 165, 167, 169, 180 and
+// 181): model 2, module size, error correction, store the data and print it.
+func writeEscPosQR(buf *bytes.Buffer, data string, level qrcode.ErrorCorrectionLevel, module int) {
+	fn := func(args ...byte) {
+		buf.Write([]byte{0x1d, 0x28, 0x6b, byte(len(args)), byte(len(args) >> 8)})
+		buf.Write(args)
+	}
+	fn(0x31, 0x41, 0x32, 0x00)         // model 2
+	fn(0x31, 0x43, byte(module))       // module size in dots
+	fn(0x31, 0x45, escPosLevel(level)) // error correction
+	fn(append([]byte{0x31, 0x50, 0x30}, data...)...)
+	fn(0x31, 0x51, 0x30) // print the stored symbol
+}
+
+func escPosLevel(level qrcode.ErrorCorrectionLevel) byte {
+	switch level.String() {
+	case "L":
+		return 0x30
+	case "Q":
+		return 0x32
+	case "H":
+		return 0x33
+	default:
+		return 0x31
+	}
+}
+
+// writeEscPosRaster prints the matrix, quiet zone included, as raster bit images
+// (GS v 0), escPosRasterBand dot rows per command.
+func writeEscPosRaster(buf *bytes.Buffer, m *qrcode.Matrix, module int) {
+	dots := m.Dimension() * module
+	rowBytes := (dots + 7) / 8
+	for top := 0; top < dots; top += escPosRasterBand {
+		rows := dots - top
+		if rows > escPosRasterBand {
+			rows = escPosRasterBand
+		}
+		buf.Write([]byte{0x1d, 0x76, 0x30, 0x00, byte(rowBytes), byte(rowBytes >> 8), byte(rows), byte(rows >> 8)})
+		for y := top; y < top+rows; y++ {
+			row := make([]byte, rowBytes)
+			for x := 0; x < dots; x++ {
+				if m.Get(x/module, y/module) {
+					row[x/8] |= 0x80 >> (x % 8)
+				}
+			}
+			buf.Write(row)
+		}
+	}
+}
+
+// escPosText makes text printable with the default code page (ASCII, accents removed) and
+// truncates it to the line width.
+func escPosText(s string, columns int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, normalizeChars(s))
+	if len(s) > columns {
+		s = s[:columns]
+	}
+	return s
+}
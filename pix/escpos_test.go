@@ -0,0 +1,94 @@
+package pix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+func TestNewEscPos(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+
+	data, err := NewEscPos(parsed, EscPosOptions{PaperWidth: 58, Level: qrcode.Quartile, ModuleSize: 6})
+	if err != nil {
+		t.Fatalf("render escpos: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte{0x1b, 0x40}) {
+		t.Fatalf("expected the stream to start with ESC @")
+	}
+	if !bytes.Contains(data, []byte("FULANO DE TAL\n")) || !bytes.Contains(data, []byte("BRASILIA\n")) {
+		t.Fatalf("expected merchant header lines")
+	}
+	store := append([]byte{0x1d, 0x28, 0x6b, byte(len(parsed.Raw) + 3), 0x00, 0x31, 0x50, 0x30}, parsed.Raw...)
+	if !bytes.Contains(data, store) {
+		t.Fatalf("expected the payload stored with GS ( k")
+	}
+	for _, cmd := range [][]byte{
+		{0x1d, 0x28, 0x6b, 0x03, 0x00, 0x31, 0x43, 0x06}, // module size
+		{0x1d, 0x28, 0x6b, 0x03, 0x00, 0x31, 0x45, 0x32}, // ECC Q
+		{0x1d, 0x28, 0x6b, 0x03, 0x00, 0x31, 0x51, 0x30}, // print
+	} {
+		if !bytes.Contains(data, cmd) {
+			t.Fatalf("missing command % x", cmd)
+		}
+	}
+	if !bytes.HasSuffix(data, []byte{0x1d, 0x56, 0x42, 0x03}) {
+		t.Fatalf("expected a paper cut at the end")
+	}
+
+	noCut, _ := NewEscPos(parsed, EscPosOptions{NoCut: true})
+	if bytes.Contains(noCut, []byte{0x1d, 0x56}) {
+		t.Fatalf("expected no cut command")
+	}
+
+	if _, err := NewEscPos(parsed, EscPosOptions{PaperWidth: 76}); err == nil {
+		t.Fatalf("expected unsupported paper width to be rejected")
+	}
+	if _, err := NewEscPos(parsed, EscPosOptions{PaperWidth: 58, ModuleSize: 16}); err == nil {
+		t.Fatalf("expected a qr code wider than the paper to be rejected")
+	}
+}
+
+func TestNewEscPosRaster(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	data, err := NewEscPos(parsed, EscPosOptions{Raster: true, ModuleSize: 4})
+	if err != nil {
+		t.Fatalf("render escpos: %v", err)
+	}
+	if bytes.Contains(data, []byte{0x1d, 0x28, 0x6b}) {
+		t.Fatalf("expected no native qr commands in raster mode")
+	}
+
+	m, _ := qrcode.Encode(parsed.Raw, 0)
+	dots := m.Dimension() * 4
+	rowBytes := (dots + 7) / 8
+	i := bytes.Index(data, []byte{0x1d, 0x76, 0x30, 0x00})
+	if i < 0 {
+		t.Fatalf("expected a GS v 0 raster image")
+	}
+	// read back the bands and compare every dot with the matrix
+	for y := 0; y < dots; {
+		hdr := data[i : i+8]
+		if w := int(hdr[4]) | int(hdr[5])<<8; w != rowBytes {
+			t.Fatalf("band width %d bytes, want %d", w, rowBytes)
+		}
+		rows := int(hdr[6]) | int(hdr[7])<<8
+		img := data[i+8 : i+8+rows*rowBytes]
+		for r := 0; r < rows; r, y = r+1, y+1 {
+			for x := 0; x < dots; x++ {
+				set := img[r*rowBytes+x/8]&(0x80>>(x%8)) != 0
+				if set != m.Get(x/4, y/4) {
+					t.Fatalf("dot %d,%d does not match the matrix", x, y)
+				}
+			}
+		}
+		i += 8 + rows*rowBytes
+	}
+}
@@ -0,0 +1,178 @@
+package pix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+// HTMLOptions configures the checkout page renderer.
+type HTMLOptions struct {
+	// Title is the page title (default "Pagamento Pix" followed by the merchant name).
+	Title string
+	// ExpiresAt adds a countdown below the QR Code when set; once it is reached the page
+	// marks the charge as expired and disables the copy button.
+	ExpiresAt *time.Time
+	// Level and Logo configure the QR Code as in qrcode.SVGOptions.
+	Level qrcode.ErrorCorrectionLevel
+	Logo  *qrcode.Logo
+}
+
+type htmlPage struct {
+	Title       string
+	Merchant    string
+	City        string
+	Amount      string
+	TxID        string
+	Payload     string
+	QRCode      template.HTML
+	ExpiresAt   string
+	ExpiresText string
+}
+
+var htmlTemplate = template.Must(template.New("checkout").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+<style>
+*{box-sizing:border-box}
+body{margin:0;min-height:100vh;display:flex;align-items:center;justify-content:center;background:#f2f5f7;color:#1f2933;font:16px/1.4 system-ui,-apple-system,"Segoe UI",Roboto,Helvetica,Arial,sans-serif}
+main{width:100%;max-width:420px;margin:16px;padding:28px 24px;background:#fff;border-radius:16px;box-shadow:0 4px 24px rgba(31,41,51,.08);text-align:center}
+h1{margin:0;font-size:1.35rem}
+.city{margin:2px 0 0;color:#616e7c}
+.amount{margin:12px 0 0;font-size:2rem;font-weight:700;color:#008577}
+.qr{width:100%;max-width:280px;margin:16px auto}
+.qr svg{display:block;width:100%;height:auto}
+.label{margin:0 0 6px;font-weight:600}
+textarea{width:100%;height:5.5em;padding:8px;resize:none;border:1px solid #cbd2d9;border-radius:8px;font:12px/1.4 ui-monospace,Menlo,Consolas,monospace;word-break:break-all;color:#1f2933;background:#f9fafb}
+button{width:100%;margin-top:10px;padding:12px;border:0;border-radius:8px;background:#008577;color:#fff;font:inherit;font-weight:600;cursor:pointer}
+button:disabled{background:#9aa5b1;cursor:default}
+.meta{margin:12px 0 0;font-size:.85rem;color:#616e7c}
+.expired .qr{opacity:.25}
+.expired .expires{color:#c0392b;font-weight:600}
+</style>
+</head>
+<body>
+<main id="checkout">
+{{if .Merchant}}<h1>{{.Merchant}}</h1>{{end}}
+{{if .City}}<p class="city">{{.City}}</p>{{end}}
+{{if .Amount}}<p class="amount">{{.Amount}}</p>{{end}}
+<div class="qr" role="img" aria-label="QR Code Pix">{{.QRCode}}</div>
+{{if .ExpiresAt}}<p class="meta expires" id="expires" data-expires="{{.ExpiresAt}}">{{.ExpiresText}}</p>{{end}}
+<p class="label"><label for="payload">Pix Copia e Cola</label></p>
+<textarea id="payload" readonly>{{.Payload}}</textarea>
+<button type="button" id="copy">Copiar código</button>
+{{if .TxID}}<p class="meta">TxID: {{.TxID}}</p>{{end}}
+</main>
+<script>
+(function () {
+  var button = document.getElementById("copy");
+  var payload = document.getElementById("payload");
+  function copied() {
+    button.textContent = "Código copiado!";
+    setTimeout(function () { button.textContent = "Copiar código"; }, 2000);
+  }
+  function fallback() {
+    payload.select();
+    try { document.execCommand("copy"); copied(); } catch (e) {}
+  }
+  button.addEventListener("click", function () {
+    if (navigator.clipboard && window.isSecureContext) {
+      navigator.clipboard.writeText(payload.value).then(copied, fallback);
+    } else {
+      fallback();
+    }
+  });
+
+  var expires = document.getElementById("expires");
+  if (!expires) return;
+  var deadline = Date.parse(expires.getAttribute("data-expires"));
+  function pad(n) { return (n < 10 ? "0" : "") + n; }
+  function tick() {
+    var left = Math.floor((deadline - Date.now()) / 1000);
+    if (left <= 0) {
+      expires.textContent = "Cobrança expirada";
+      document.getElementById("checkout").className = "expired";
+      button.disabled = true;
+      return;
+    }
+    var h = Math.floor(left / 3600), m = Math.floor(left % 3600 / 60), s = left % 60;
+    expires.textContent = "Expira em " + (h > 0 ? h + ":" + pad(m) : m) + ":" + pad(s);
+    setTimeout(tick, 1000 - Date.now() % 1000);
+  }
+  tick();
+})();
+</script>
+</body>
+</html>
+`))
+
+// NewHTML renders a self-contained checkout page for parsed: the QR Code as inline SVG,
+// merchant, formatted amount, the copia-e-cola with a copy-to-clipboard button and an
+// optional expiry countdown. The page loads no external resources and works without
+// JavaScript, except for the copy button and the live countdown.
+func NewHTML(parsed *ParsedPayload, opts HTMLOptions) ([]byte, error) {
+	if parsed == nil || parsed.Raw == "" {
+		return nil, errors.New("pix: html page requires a parsed payload")
+	}
+	qr, err := qrcode.NewSVG(qrcode.SVGOptions{Content: parsed.Raw, Level: opts.Level, Logo: opts.Logo})
+	if err != nil {
+		return nil, err
+	}
+	// inline the svg element, letting the stylesheet size it
+	doc := string(qr)
+	doc = doc[strings.Index(doc, "<svg"):]
+	loc := svgSizeAttrs.FindStringIndex(doc)
+	if loc == nil {
+		return nil, errors.New("pix: unexpected qrcode svg")
+	}
+	doc = strings.TrimRight(doc[:loc[0]], " ") + doc[loc[1]:]
+
+	page := htmlPage{
+		Title:    opts.Title,
+		Merchant: parsed.MerchantName,
+		City:     parsed.MerchantCity,
+		Payload:  parsed.Raw,
+		// the generated svg contains only paths and the escaped logo data URI
+		QRCode: template.HTML(strings.TrimSpace(doc)),
+	}
+	if page.Title == "" {
+		page.Title = strings.TrimSpace("Pagamento Pix " + parsed.MerchantName)
+	}
+	if parsed.TransactionAmount != "" {
+		page.Amount = formatBRL(parsed.TransactionAmount)
+	}
+	if txid := parsed.AdditionalDataField.TxID; txid != "***" {
+		page.TxID = txid
+	}
+	if opts.ExpiresAt != nil {
+		page.ExpiresAt = opts.ExpiresAt.Format(time.RFC3339)
+		page.ExpiresText = "Expira em " + opts.ExpiresAt.Format("02/01/2006 15:04")
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, page); err != nil {
+		return nil, fmt.Errorf("pix: render html: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// NewDynamicHTML renders the checkout page of a fetched dynamic payload, counting down
+// to its expiry unless opts.ExpiresAt is set.
+func NewDynamicHTML(d *DynamicPayload, opts HTMLOptions) ([]byte, error) {
+	if d == nil {
+		return nil, errors.New("pix: html page requires a dynamic payload")
+	}
+	if opts.ExpiresAt == nil {
+		opts.ExpiresAt = d.ExpiresAt
+	}
+	return NewHTML(d.Parsed, opts)
+}
@@ -0,0 +1,102 @@
+package pix
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewHTML(t *testing.T) {
+	p, err := New(
+		OptPixKey("+5511999999999"),
+		OptMerchantName("Padaria Central"),
+		OptMerchantCity("SAO PAULO"),
+		OptAmount("1234.50"),
+		OptTxId("PEDIDO42"),
+	)
+	if err != nil {
+		t.Fatalf("new pix: %v", err)
+	}
+	payload, err := p.GenPayload()
+	if err != nil {
+		t.Fatalf("gen payload: %v", err)
+	}
+	parsed, err := ParsePayload(payload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+
+	page, err := NewHTML(parsed, HTMLOptions{})
+	if err != nil {
+		t.Fatalf("render html: %v", err)
+	}
+	html := string(page)
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>Pagamento Pix PADARIA CENTRAL</title>",
+		"<h1>PADARIA CENTRAL</h1>",
+		"R$ 1.234,50",
+		"TxID: PEDIDO42",
+		// html/template escapes the + of phone keys; the textarea value is unchanged
+		`<textarea id="payload" readonly>` + strings.ReplaceAll(payload, "+", "&#43;") + "</textarea>",
+		`version="1.1" viewBox="0 0 `,
+		"navigator.clipboard",
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("expected %q in page:\n%s", want, html)
+		}
+	}
+	// single file: no external stylesheets, scripts or images, and a responsive QR Code
+	for _, unwanted := range []string{"<?xml", `src="http`, `href="http`, `<svg width=`, `version="1.1" width=`} {
+		if strings.Contains(html, unwanted) {
+			t.Fatalf("unexpected %q in page", unwanted)
+		}
+	}
+	if strings.Contains(html, `id="expires"`) {
+		t.Fatalf("expected no countdown without an expiry")
+	}
+
+	// text comes from payloads read elsewhere and must be escaped
+	hostile := *parsed
+	hostile.MerchantName = `<script>alert("x")</script>`
+	page, err = NewHTML(&hostile, HTMLOptions{Title: "Loja & Cia"})
+	if err != nil {
+		t.Fatalf("render html: %v", err)
+	}
+	if html := string(page); strings.Contains(html, `<script>alert`) || !strings.Contains(html, "<title>Loja &amp; Cia</title>") {
+		t.Fatalf("expected escaped text in page")
+	}
+
+	if _, err := NewHTML(nil, HTMLOptions{}); err == nil {
+		t.Fatalf("expected a nil payload to be rejected")
+	}
+}
+
+func TestNewDynamicHTML(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	expires := time.Date(2030, 1, 2, 15, 4, 0, 0, time.UTC)
+	page, err := NewDynamicHTML(&DynamicPayload{Raw: parsed.Raw, Parsed: parsed, ExpiresAt: &expires}, HTMLOptions{})
+	if err != nil {
+		t.Fatalf("render html: %v", err)
+	}
+	html := string(page)
+	for _, want := range []string{
+		`data-expires="2030-01-02T15:04:00Z"`,
+		"Expira em 02/01/2030 15:04",
+		"Cobrança expirada",
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("expected %q in page", want)
+		}
+	}
+	// the *** placeholder is not a TxID worth showing
+	if strings.Contains(html, "TxID:") {
+		t.Fatalf("unexpected txid line in page")
+	}
+	if _, err := NewDynamicHTML(nil, HTMLOptions{}); err == nil {
+		t.Fatalf("expected a nil dynamic payload to be rejected")
+	}
+}
@@ -0,0 +1,65 @@
+package pix
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // screenshots and scans are often JPEG
+	_ "image/png"
+	"io"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+// ParseImage reads a PNG or JPEG image, or a PDF document, decodes every QR Code in it and
+// parses the Pix payloads, in reading order (page by page for PDFs, see qrcode.DecodePDF).
+// Codes that are not valid Pix payloads (links, other EMV schemes) are skipped; an error is
+// returned when no Pix payload is found.
+func ParseImage(r io.Reader) ([]*ParsedPayload, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(5); bytes.Equal(magic, []byte("%PDF-")) {
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return nil, fmt.Errorf("pix: read pdf: %w", err)
+		}
+		codes, err := qrcode.DecodePDF(data)
+		if err != nil {
+			return nil, err
+		}
+		return parseDecoded(codes)
+	}
+	img, _, err := image.Decode(br)
+	if err != nil {
+		return nil, fmt.Errorf("pix: decode image: %w", err)
+	}
+	return ParseQRCodes(img)
+}
+
+// ParseQRCodes is ParseImage for an already decoded image.
+func ParseQRCodes(img image.Image) ([]*ParsedPayload, error) {
+	codes, err := qrcode.Decode(img)
+	if err != nil {
+		return nil, err
+	}
+	return parseDecoded(codes)
+}
+
+func parseDecoded(codes []qrcode.Decoded) ([]*ParsedPayload, error) {
+	var parsed []*ParsedPayload
+	var firstErr error
+	for _, code := range codes {
+		p, err := ParsePayload(code.Content)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		parsed = append(parsed, p)
+	}
+	if len(parsed) == 0 {
+		return nil, fmt.Errorf("pix: no pix payload among %d qr code(s): %w", len(codes), firstErr)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,55 @@
+package pix
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+func TestParseImage(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	// a payment card is close to the screenshots this is meant for
+	card, err := NewCardPNG(parsed, CardOptions{})
+	if err != nil {
+		t.Fatalf("render card: %v", err)
+	}
+
+	got, err := ParseImage(bytes.NewReader(card))
+	if err != nil {
+		t.Fatalf("parse image: %v", err)
+	}
+	if len(got) != 1 || got[0].Raw != bacenSamplePayload {
+		t.Fatalf("unexpected payloads %+v", got)
+	}
+
+	link, err := qrcode.New(qrcode.QRCodeOptions{Content: "https://example.com"})
+	if err != nil {
+		t.Fatalf("render qrcode: %v", err)
+	}
+	if _, err := ParseImage(bytes.NewReader(link)); err == nil {
+		t.Fatalf("expected a non-pix qr code to be rejected")
+	}
+	if _, err := ParseImage(bytes.NewReader([]byte("not an image"))); err == nil {
+		t.Fatalf("expected invalid images to be rejected")
+	}
+
+	pdf, err := qrcode.NewPDF(qrcode.PDFOptions{Content: bacenSamplePayload, CropMarks: true})
+	if err != nil {
+		t.Fatalf("render pdf: %v", err)
+	}
+	got, err = ParseImage(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("parse pdf: %v", err)
+	}
+	if len(got) != 1 || got[0].Raw != bacenSamplePayload {
+		t.Fatalf("unexpected pdf payloads %+v", got)
+	}
+	if _, err := ParseImage(bytes.NewReader([]byte("%PDF-1.4\n"))); !errors.Is(err, qrcode.ErrNotFound) {
+		t.Fatalf("expected an empty pdf to hold no qr code, got %v", err)
+	}
+}
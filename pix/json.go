@@ -0,0 +1,119 @@
+package pix
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// jsonNumberPattern matches the number grammar of RFC 8259.
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?([eE][+-]?\d+)?$`)
+
+// parsedPayloadJSON is the stable wire representation of ParsedPayload.
+type parsedPayloadJSON struct {
+	Raw                     string            `json:"raw"`
+	Kind                    string            `json:"kind"`
+	PayloadFormatIndicator  string            `json:"payloadFormatIndicator"`
+	PointOfInitiationMethod string            `json:"pointOfInitiationMethod,omitempty"`
+	MerchantCategoryCode    string            `json:"merchantCategoryCode,omitempty"`
+	TransactionCurrency     string            `json:"transactionCurrency,omitempty"`
+	TransactionAmount       json.Number       `json:"transactionAmount,omitempty"`
+	CountryCode             string            `json:"countryCode"`
+	MerchantName            string            `json:"merchantName"`
+	MerchantCity            string            `json:"merchantCity"`
+	CRC                     string            `json:"crc"`
+	MerchantAccounts        []MerchantAccount `json:"merchantAccounts"`
+	AdditionalDataField     AdditionalData    `json:"additionalDataField"`
+	Tags                    []*TLV            `json:"tags"`
+}
+
+// dynamicPayloadJSON is the stable wire representation of DynamicPayload.
+type dynamicPayloadJSON struct {
+	Raw       string         `json:"raw"`
+	Parsed    *ParsedPayload `json:"parsed,omitempty"`
+	ExpiresAt *time.Time     `json:"expiresAt,omitempty"`
+}
+
+// MarshalJSON encodes the payload with camelCase fields, the transaction amount as a JSON
+// number and the top-level tags as a list in payload order. Amounts that are not valid JSON
+// numbers (ParsePayload accepts any tag 54 value, such as "10,00") are left out; they remain
+// available in raw and in the tag list.
+func (p ParsedPayload) MarshalJSON() ([]byte, error) {
+	wire := parsedPayloadJSON{
+		Raw:                     p.Raw,
+		Kind:                    p.Kind().String(),
+		PayloadFormatIndicator:  p.PayloadFormatIndicator,
+		PointOfInitiationMethod: p.PointOfInitiationMethod,
+		MerchantCategoryCode:    p.MerchantCategoryCode,
+		TransactionCurrency:     p.TransactionCurrency,
+		CountryCode:             p.CountryCode,
+		MerchantName:            p.MerchantName,
+		MerchantCity:            p.MerchantCity,
+		CRC:                     p.CRC,
+		MerchantAccounts:        p.MerchantAccounts,
+		AdditionalDataField:     p.AdditionalDataField,
+		Tags:                    p.orderedTags(),
+	}
+	if jsonNumberPattern.MatchString(p.TransactionAmount) {
+		wire.TransactionAmount = json.Number(p.TransactionAmount)
+	}
+	if wire.MerchantAccounts == nil {
+		wire.MerchantAccounts = []MerchantAccount{}
+	}
+	if wire.AdditionalDataField.Raw == nil {
+		wire.AdditionalDataField.Raw = map[string]string{}
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON restores a payload from its JSON representation. Only raw is read: it is
+// parsed again, so the decoded value is always consistent with (and validated against) the
+// EMV data, and every other field of the document is ignored. Edits to those fields are
+// lost; change the payload through pix.New or edit raw and its CRC instead.
+func (p *ParsedPayload) UnmarshalJSON(data []byte) error {
+	var wire parsedPayloadJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	parsed, err := ParsePayload(wire.Raw)
+	if err != nil {
+		return fmt.Errorf("decode parsed payload: %w", err)
+	}
+	*p = *parsed
+	return nil
+}
+
+// orderedTags returns the top-level tags in payload order, falling back to tag order
+// when the value was not produced by ParsePayload.
+func (p ParsedPayload) orderedTags() []*TLV {
+	if p.entries != nil {
+		return p.entries
+	}
+	keys := make([]string, 0, len(p.Tags))
+	for k := range p.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	tags := make([]*TLV, 0, len(keys))
+	for _, k := range keys {
+		tags = append(tags, p.Tags[k])
+	}
+	return tags
+}
+
+// MarshalJSON encodes the dynamic payload with camelCase fields.
+func (d DynamicPayload) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dynamicPayloadJSON(d))
+}
+
+// UnmarshalJSON decodes a dynamic payload previously encoded with MarshalJSON.
+func (d *DynamicPayload) UnmarshalJSON(data []byte) error {
+	var wire dynamicPayloadJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	*d = DynamicPayload(wire)
+	return nil
+}
@@ -0,0 +1,138 @@
+package pix
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsedPayloadJSON(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	parsed.TransactionAmount = "10.50"
+
+	data, err := json.Marshal(parsed)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var generic map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if generic["merchantName"] != "Fulano de Tal" || generic["kind"] != "STATIC" {
+		t.Fatalf("unexpected json: %s", data)
+	}
+	if amount, ok := generic["transactionAmount"].(json.Number); !ok || amount.String() != "10.50" {
+		t.Fatalf("expected numeric amount, got %#v", generic["transactionAmount"])
+	}
+
+	tags, ok := generic["tags"].([]interface{})
+	if !ok || len(tags) != 9 {
+		t.Fatalf("expected 9 ordered tags, got %#v", generic["tags"])
+	}
+	var order []string
+	for _, tag := range tags {
+		order = append(order, tag.(map[string]interface{})["tag"].(string))
+	}
+	if got := strings.Join(order, ","); got != "00,26,52,53,58,59,60,62,63" {
+		t.Fatalf("unexpected tag order %s", got)
+	}
+}
+
+func TestParsedPayloadJSONInvalidAmount(t *testing.T) {
+	// a valid CRC over a non-numeric amount still parses
+	body := strings.Replace(bacenSamplePayload[:len(bacenSamplePayload)-4], "5303986", "5303986540510,00", 1)
+	parsed, err := ParsePayload(body + ComputeCRC(body))
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	if parsed.TransactionAmount != "10,00" {
+		t.Fatalf("unexpected amount %q", parsed.TransactionAmount)
+	}
+
+	data, err := json.Marshal(parsed)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if bytes.Contains(data, []byte(`"transactionAmount"`)) {
+		t.Fatalf("expected the invalid amount to be left out: %s", data)
+	}
+	if !bytes.Contains(data, []byte(`"value":"10,00"`)) {
+		t.Fatalf("expected the amount in the tag list: %s", data)
+	}
+
+	var decoded ParsedPayload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if decoded.TransactionAmount != "10,00" {
+		t.Fatalf("unexpected decoded amount %q", decoded.TransactionAmount)
+	}
+}
+
+func TestParsedPayloadJSONRoundTrip(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	expires := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	original := DynamicPayload{Raw: bacenSamplePayload, Parsed: parsed, ExpiresAt: &expires}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"expiresAt":"2026-01-02T03:04:05Z"`)) {
+		t.Fatalf("unexpected json: %s", data)
+	}
+
+	var decoded DynamicPayload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if decoded.Parsed == nil || decoded.Parsed.AdditionalDataField.TxID != "***" {
+		t.Fatalf("unexpected decoded payload: %+v", decoded.Parsed)
+	}
+	if decoded.ExpiresAt == nil || !decoded.ExpiresAt.Equal(expires) {
+		t.Fatalf("unexpected expiration: %v", decoded.ExpiresAt)
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("marshal again: %v", err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Fatalf("json representation is not stable:\n%s\n%s", data, again)
+	}
+}
+
+func TestPublishedSchemasUpToDate(t *testing.T) {
+	schemas := map[string]func() ([]byte, error){
+		"parsed-payload.schema.json":  ParsedPayloadSchema,
+		"dynamic-payload.schema.json": DynamicPayloadSchema,
+	}
+
+	for name, gen := range schemas {
+		want, err := gen()
+		if err != nil {
+			t.Fatalf("generate %s: %v", name, err)
+		}
+		got, err := os.ReadFile(filepath.Join("..", "docs", "schema", name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("%s is outdated; run go generate ./pix", name)
+		}
+	}
+}
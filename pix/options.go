@@ -1,5 +1,14 @@
 package pix
 
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
 // PixKind defines if the QR Code is static or dynamic.
 type PixKind int
 
@@ -19,6 +28,19 @@ func (k PixKind) String() string {
 	}
 }
 
+// ParseKind parses the name of a kind, "static" or "dynamic" in any case; an empty name is
+// STATIC.
+func ParseKind(kind string) (PixKind, error) {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "", "static":
+		return STATIC, nil
+	case "dynamic":
+		return DYNAMIC, nil
+	default:
+		return STATIC, fmt.Errorf("invalid kind %q (expected static or dynamic)", kind)
+	}
+}
+
 // Options pattern for configuring Pix parameters.
 type Options func(o *OptionsParams) error
 
@@ -43,6 +65,18 @@ type OptionsParams struct {
 	asciiWhite    string
 	asciiQuiet    bool
 	asciiQuietSet bool
+	asciiHalf     bool
+	asciiInverted bool
+	asciiWidth    int
+	qrQuietZone   int
+	qrQuietSet    bool
+	svgModuleSize int
+	qrLevel       qrcode.ErrorCorrectionLevel
+	qrForeground  color.Color
+	qrBackground  color.Color
+	qrTransparent bool
+	qrLogo        *qrcode.Logo
+	qrCache       *qrcode.Cache
 }
 
 // Functional options (setters)
@@ -87,21 +121,118 @@ func OptASCIIQuietZone(enabled bool) Options {
 		return nil
 	}
 }
+func OptASCIIHalfBlock(inverted bool) Options {
+	return func(o *OptionsParams) error {
+		o.asciiHalf = true
+		o.asciiInverted = inverted
+		return nil
+	}
+}
+func OptASCIITerminalWidth(columns int) Options {
+	return func(o *OptionsParams) error {
+		if columns < 0 {
+			return errors.New("terminal width must not be negative")
+		}
+		o.asciiWidth = columns
+		return nil
+	}
+}
+func OptQRCodeQuietZone(modules int) Options {
+	return func(o *OptionsParams) error {
+		if modules < 0 {
+			return errors.New("qrcode quiet zone must not be negative")
+		}
+		o.qrQuietSet = true
+		o.qrQuietZone = modules
+		return nil
+	}
+}
+func OptSVGModuleSize(v int) Options {
+	return func(o *OptionsParams) error {
+		if v <= 0 {
+			return errors.New("svg module size must be greater than zero")
+		}
+		o.svgModuleSize = v
+		return nil
+	}
+}
+func OptQRCodeErrorCorrection(level qrcode.ErrorCorrectionLevel) Options {
+	return func(o *OptionsParams) error {
+		if level < 0 || level > qrcode.High {
+			return errors.New("invalid qrcode error correction level")
+		}
+		o.qrLevel = level
+		return nil
+	}
+}
+func OptQRCodeColors(fg, bg color.Color) Options {
+	return func(o *OptionsParams) error {
+		check, against := fg, bg
+		if check == nil {
+			check = color.Black
+		}
+		if against == nil {
+			against = color.White
+		}
+		if err := qrcode.CheckContrast(check, against); err != nil {
+			return err
+		}
+		o.qrForeground = fg
+		o.qrBackground = bg
+		return nil
+	}
+}
+func OptQRCodeTransparentBackground(enabled bool) Options {
+	return func(o *OptionsParams) error { o.qrTransparent = enabled; return nil }
+}
+
+func OptQRCodeLogo(logo qrcode.Logo) Options {
+	return func(o *OptionsParams) error {
+		if len(logo.Data) == 0 {
+			return errors.New("qrcode logo must not be empty")
+		}
+		if logo.Coverage < 0 || logo.Coverage > qrcode.MaxLogoCoverage {
+			return errors.New("invalid qrcode logo coverage")
+		}
+		o.qrLogo = &logo
+		return nil
+	}
+}
+
+// OptQRCodeCache shares a PNG render cache between Pix values; identical QR Codes are
+// then encoded once. Useful for servers rendering the same charges repeatedly.
+func OptQRCodeCache(c *qrcode.Cache) Options {
+	return func(o *OptionsParams) error { o.qrCache = c; return nil }
+}
 
 // Getters
-func (o *OptionsParams) GetTxId() string           { return o.txId }
-func (o *OptionsParams) GetPixKey() string         { return o.pixKey }
-func (o *OptionsParams) GetDescription() string    { return o.description }
-func (o *OptionsParams) GetMerchantName() string   { return o.merchant.name }
-func (o *OptionsParams) GetMerchantCity() string   { return o.merchant.city }
-func (o *OptionsParams) GetAmount() string         { return o.amount }
-func (o *OptionsParams) GetKind() PixKind          { return o.kind }
-func (o *OptionsParams) GetAdditionalInfo() string { return o.additional }
-func (o *OptionsParams) GetUrl() string            { return o.url }
-func (o *OptionsParams) GetQRCodeSize() int        { return o.qrcodeSize }
-func (o *OptionsParams) GetQRCodeContent() string  { return o.qrcodeContent }
-func (o *OptionsParams) GetASCIIQrScale() int      { return o.qrcodeScale }
-func (o *OptionsParams) GetASCIIQrBlack() string   { return o.asciiBlack }
-func (o *OptionsParams) GetASCIIQrWhite() string   { return o.asciiWhite }
-func (o *OptionsParams) GetASCIIQuietZone() bool   { return o.asciiQuiet }
-func (o *OptionsParams) HasASCIIQuietZone() bool   { return o.asciiQuietSet }
+func (o *OptionsParams) GetTxId() string            { return o.txId }
+func (o *OptionsParams) GetPixKey() string          { return o.pixKey }
+func (o *OptionsParams) GetDescription() string     { return o.description }
+func (o *OptionsParams) GetMerchantName() string    { return o.merchant.name }
+func (o *OptionsParams) GetMerchantCity() string    { return o.merchant.city }
+func (o *OptionsParams) GetAmount() string          { return o.amount }
+func (o *OptionsParams) GetKind() PixKind           { return o.kind }
+func (o *OptionsParams) GetAdditionalInfo() string  { return o.additional }
+func (o *OptionsParams) GetUrl() string             { return o.url }
+func (o *OptionsParams) GetQRCodeSize() int         { return o.qrcodeSize }
+func (o *OptionsParams) GetQRCodeContent() string   { return o.qrcodeContent }
+func (o *OptionsParams) GetASCIIQrScale() int       { return o.qrcodeScale }
+func (o *OptionsParams) GetASCIIQrBlack() string    { return o.asciiBlack }
+func (o *OptionsParams) GetASCIIQrWhite() string    { return o.asciiWhite }
+func (o *OptionsParams) GetASCIIQuietZone() bool    { return o.asciiQuiet }
+func (o *OptionsParams) HasASCIIQuietZone() bool    { return o.asciiQuietSet }
+func (o *OptionsParams) GetASCIIHalfBlock() bool    { return o.asciiHalf }
+func (o *OptionsParams) GetASCIIInverted() bool     { return o.asciiInverted }
+func (o *OptionsParams) GetASCIITerminalWidth() int { return o.asciiWidth }
+func (o *OptionsParams) GetQRCodeQuietZone() int    { return o.qrQuietZone }
+func (o *OptionsParams) HasQRCodeQuietZone() bool   { return o.qrQuietSet }
+func (o *OptionsParams) GetSVGModuleSize() int      { return o.svgModuleSize }
+func (o *OptionsParams) GetQRCodeErrorCorrection() qrcode.ErrorCorrectionLevel {
+	return o.qrLevel
+}
+func (o *OptionsParams) GetQRCodeForeground() color.Color     { return o.qrForeground }
+func (o *OptionsParams) GetQRCodeBackground() color.Color     { return o.qrBackground }
+func (o *OptionsParams) GetQRCodeTransparentBackground() bool { return o.qrTransparent }
+func (o *OptionsParams) GetQRCodeLogo() *qrcode.Logo          { return o.qrLogo }
+func (o *OptionsParams) GetQRCodeCache() *qrcode.Cache        { return o.qrCache }
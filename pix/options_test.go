@@ -59,3 +59,24 @@ func TestSetQRCodeContent(t *testing.T) {
 		t.Errorf("SetQRCodeContent did not set value correctly: got %v; want 'QR Code Content'", params.GetQRCodeContent())
 	}
 }
+
+func TestParseKind(t *testing.T) {
+	tests := []struct {
+		in   string
+		want PixKind
+		err  bool
+	}{
+		{"", STATIC, false},
+		{"static", STATIC, false},
+		{" Dynamic ", DYNAMIC, false},
+		{"DYNAMIC", DYNAMIC, false},
+		{"recurring", STATIC, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseKind(test.in)
+		if (err != nil) != test.err || got != test.want {
+			t.Errorf("ParseKind(%q) = %v, %v; want %v (error %v)", test.in, got, err, test.want, test.err)
+		}
+	}
+}
@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-
-	"github.com/snksoft/crc"
 )
 
 var (
@@ -18,25 +16,25 @@ var (
 
 // TLV represents an EMV tag-length-value entry.
 type TLV struct {
-	Tag     string
-	Value   string
-	Entries []*TLV
+	Tag     string `json:"tag"`
+	Value   string `json:"value"`
+	Entries []*TLV `json:"entries,omitempty"`
 }
 
 // MerchantAccount describes the parsed Merchant Account Information template.
 type MerchantAccount struct {
-	ID             string
-	GUI            string
-	PixKey         string
-	AdditionalInfo string
-	URL            string
-	Raw            map[string]string
+	ID             string            `json:"id"`
+	GUI            string            `json:"gui"`
+	PixKey         string            `json:"pixKey,omitempty"`
+	AdditionalInfo string            `json:"additionalInfo,omitempty"`
+	URL            string            `json:"url,omitempty"`
+	Raw            map[string]string `json:"raw"`
 }
 
 // AdditionalData captures parsed Additional Data Field Template values.
 type AdditionalData struct {
-	Raw  map[string]string
-	TxID string
+	Raw  map[string]string `json:"raw"`
+	TxID string            `json:"txid,omitempty"`
 }
 
 // ParsedPayload contains the structured Pix payload after parsing.
@@ -54,6 +52,9 @@ type ParsedPayload struct {
 	MerchantAccounts        []MerchantAccount
 	AdditionalDataField     AdditionalData
 	Tags                    map[string]*TLV
+
+	// entries keeps the top-level tags in payload order for serialization.
+	entries []*TLV
 }
 
 // Kind returns the Pix kind inferred from the payload.
@@ -95,15 +96,16 @@ func ParsePayload(payload string) (*ParsedPayload, error) {
 	}
 
 	expectedCRC := strings.ToUpper(crcTLV.Value)
-	recalculatedCRC := strings.ToUpper(fmt.Sprintf("%04X", crc.CalculateCRC(crc.CCITT, []byte(payload[:len(payload)-4]))))
+	recalculatedCRC := ComputeCRC(payload[:len(payload)-4])
 	if expectedCRC != recalculatedCRC {
 		return nil, fmt.Errorf("crc mismatch: expected %s got %s", expectedCRC, recalculatedCRC)
 	}
 
 	result := &ParsedPayload{
-		Raw:  payload,
-		CRC:  expectedCRC,
-		Tags: topLevel,
+		Raw:     payload,
+		CRC:     expectedCRC,
+		Tags:    topLevel,
+		entries: tlvs,
 	}
 
 	if v, ok := topLevel[TAG_INIT]; ok {
@@ -132,10 +134,14 @@ func ParsePayload(payload string) (*ParsedPayload, error) {
 	}
 
 	var merchantAccounts []MerchantAccount
-	for tag, tlv := range topLevel {
-		if isMerchantAccountTag(tag) {
+	for _, tlv := range tlvs {
+		// iterate in payload order so merchant accounts are deterministic
+		if topLevel[tlv.Tag] != tlv {
+			continue
+		}
+		if isMerchantAccountTag(tlv.Tag) {
 			account := MerchantAccount{
-				ID:  tag,
+				ID:  tlv.Tag,
 				Raw: make(map[string]string),
 			}
 
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"strings"
 	"testing"
-
-	"github.com/snksoft/crc"
 )
 
 func TestParsePayloadStatic(t *testing.T) {
@@ -113,6 +111,5 @@ func buildPayloadWithoutMerchantName() string {
 	}, "")
 
 	base := payload[:len(payload)-4]
-	checksum := fmt.Sprintf("%04X", crc.CalculateCRC(crc.CCITT, []byte(base)))
-	return base + checksum
+	return base + ComputeCRC(base)
 }
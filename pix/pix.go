@@ -3,17 +3,16 @@ package pix
 import (
 	"fmt"
 	"net/url"
-	"regexp"
 	"strings"
 	"unicode"
 
-	"github.com/snksoft/crc"
 	"github.com/thiagozs/go-pixgen/qrcode"
 )
 
 // Pix representa o construtor principal do QR Pix
 type Pix struct {
 	params *OptionsParams
+	matrix *qrcode.Matrix
 }
 
 // New cria uma nova instância de Pix
@@ -51,9 +50,7 @@ func (p *Pix) GenPayload() (string, error) {
 	)
 
 	// Transaction Amount deve ter 2 casas decimais
-	var moneyRe = regexp.MustCompile(`^\d{1,10}\.\d{2}$`)
-
-	if amt := p.params.GetAmount(); amt != "" && moneyRe.MatchString(amt) {
+	if amt := p.params.GetAmount(); amt != "" && amountPattern.MatchString(amt) {
 		tags = append(tags, p.tlv(TAG_TRANSACTION_AMOUNT, amt))
 	}
 
@@ -80,34 +77,82 @@ func (p *Pix) GenPayload() (string, error) {
 	return payload, nil
 }
 
-// GenQRCode gera o QR Code em bytes
+// GenQRCode gera o QR Code em bytes. Com OptQRCodeCache o resultado é uma cópia do PNG em
+// cache, que pode ser alterada sem afetar as próximas chamadas
 func (p *Pix) GenQRCode() ([]byte, error) {
-	if p.params.GetQRCodeContent() == "" {
-		if _, err := p.GenPayload(); err != nil {
-			return nil, err
-		}
-	}
 	size := p.params.GetQRCodeSize()
 	if size == 0 {
 		size = 256
 	}
-	return qrcode.New(qrcode.QRCodeOptions{
-		Size:    size,
-		Content: p.params.GetQRCodeContent(),
+	opts := qrcode.QRCodeOptions{
+		Size:                  size,
+		Foreground:            p.params.GetQRCodeForeground(),
+		Background:            p.params.GetQRCodeBackground(),
+		TransparentBackground: p.params.GetQRCodeTransparentBackground(),
+		QuietZone:             p.params.GetQRCodeQuietZone(),
+		QuietZoneSet:          p.params.HasQRCodeQuietZone(),
+		Logo:                  p.params.GetQRCodeLogo(),
+	}
+
+	// com cache, a matriz só é codificada quando o PNG ainda não foi gerado
+	if cache := p.params.GetQRCodeCache(); cache != nil {
+		if p.params.GetQRCodeContent() == "" {
+			if _, err := p.GenPayload(); err != nil {
+				return nil, err
+			}
+		}
+		opts.Content, opts.Level = p.params.GetQRCodeContent(), p.qrLevel()
+		return cache.PNG(opts)
+	}
+
+	m, err := p.QRCodeMatrix()
+	if err != nil {
+		return nil, err
+	}
+	opts.Matrix = m
+	return qrcode.New(opts)
+}
+
+// GenQRCodeSVG gera o QR Code como documento SVG vetorial
+func (p *Pix) GenQRCodeSVG() ([]byte, error) {
+	m, err := p.QRCodeMatrix()
+	if err != nil {
+		return nil, err
+	}
+	return qrcode.NewSVG(qrcode.SVGOptions{
+		Matrix:       m,
+		ModuleSize:   p.params.GetSVGModuleSize(),
+		QuietZone:    p.params.GetQRCodeQuietZone(),
+		QuietZoneSet: p.params.HasQRCodeQuietZone(),
+		Logo:         p.params.GetQRCodeLogo(),
 	})
 }
 
-// GenQRCodeASCII renderiza o QR Code em arte ASCII para uso no terminal.
-func (p *Pix) GenQRCodeASCII() (string, error) {
-	if p.params.GetQRCodeContent() == "" {
-		if _, err := p.GenPayload(); err != nil {
-			return "", err
-		}
+// GenQRCodePDF gera o QR Code como PDF vetorial pronto para impressão. O conteúdo é
+// sempre o payload Pix; a borda configurada via OptQRCodeQuietZone é usada quando opts
+// não define uma. Um nível de correção em opts gera um símbolo próprio para o PDF.
+func (p *Pix) GenQRCodePDF(opts qrcode.PDFOptions) ([]byte, error) {
+	m, err := p.QRCodeMatrix()
+	if err != nil {
+		return nil, err
+	}
+	opts.Content = m.Content()
+	if opts.Level == 0 || opts.Level == m.Level() {
+		opts.Matrix = m
+	}
+	if !opts.QuietZoneSet && p.params.HasQRCodeQuietZone() {
+		opts.QuietZone = p.params.GetQRCodeQuietZone()
+		opts.QuietZoneSet = true
 	}
+	return qrcode.NewPDF(opts)
+}
 
-	scale := p.params.GetASCIIQrScale()
-	if scale <= 0 {
-		scale = 1
+// GenQRCodeASCII renderiza o QR Code em arte ASCII para uso no terminal. Com
+// OptASCIIHalfBlock usa meios-blocos Unicode, duas linhas de módulos por linha de texto.
+func (p *Pix) GenQRCodeASCII() (string, error) {
+	m, err := p.QRCodeMatrix()
+	if err != nil {
+		return "", err
 	}
 
 	quiet := false
@@ -115,8 +160,29 @@ func (p *Pix) GenQRCodeASCII() (string, error) {
 		quiet = p.params.GetASCIIQuietZone()
 	}
 
+	// meia-altura: escala 0 deixa a largura do terminal definir o tamanho
+	if p.params.GetASCIIHalfBlock() {
+		border := 1
+		if quiet {
+			border = qrcode.DefaultQuietZone
+		}
+		return qrcode.NewHalfBlock(qrcode.HalfBlockOptions{
+			Matrix:        m,
+			QuietZone:     border,
+			QuietZoneSet:  true,
+			Inverted:      p.params.GetASCIIInverted(),
+			Scale:         p.params.GetASCIIQrScale(),
+			TerminalWidth: p.params.GetASCIITerminalWidth(),
+		})
+	}
+
+	scale := p.params.GetASCIIQrScale()
+	if scale <= 0 {
+		scale = 1
+	}
+
 	return qrcode.NewASCII(qrcode.ASCIIOptions{
-		Content:      p.params.GetQRCodeContent(),
+		Matrix:       m,
 		Scale:        scale,
 		BlackChar:    p.params.GetASCIIQrBlack(),
 		WhiteChar:    p.params.GetASCIIQrWhite(),
@@ -125,6 +191,82 @@ func (p *Pix) GenQRCodeASCII() (string, error) {
 	})
 }
 
+// GenQRCodeSixel renderiza o QR Code como imagem Sixel para terminais compatíveis.
+func (p *Pix) GenQRCodeSixel() (string, error) {
+	m, err := p.QRCodeMatrix()
+	if err != nil {
+		return "", err
+	}
+	return qrcode.NewSixel(p.terminalImageOptions(m))
+}
+
+// GenQRCodeKitty renderiza o QR Code com o protocolo gráfico do terminal kitty.
+func (p *Pix) GenQRCodeKitty() (string, error) {
+	m, err := p.QRCodeMatrix()
+	if err != nil {
+		return "", err
+	}
+	return qrcode.NewKitty(p.terminalImageOptions(m))
+}
+
+func (p *Pix) terminalImageOptions(m *qrcode.Matrix) qrcode.TerminalImageOptions {
+	return qrcode.TerminalImageOptions{
+		Matrix:       m,
+		QuietZone:    p.params.GetQRCodeQuietZone(),
+		QuietZoneSet: p.params.HasQRCodeQuietZone(),
+	}
+}
+
+// QRCodeMetadata retorna versão, número de módulos, nível de correção e folga de
+// capacidade do QR Code gerado para o payload, a partir da mesma matriz de QRCodeMatrix
+func (p *Pix) QRCodeMetadata() (qrcode.Metadata, error) {
+	// com cache e sem matriz já codificada, um PNG servido do cache não força a codificação
+	if cache := p.params.GetQRCodeCache(); cache != nil && p.matrix == nil {
+		if p.params.GetQRCodeContent() == "" {
+			if _, err := p.GenPayload(); err != nil {
+				return qrcode.Metadata{}, err
+			}
+		}
+		return cache.Metadata(p.params.GetQRCodeContent(), p.qrLevel())
+	}
+	m, err := p.QRCodeMatrix()
+	if err != nil {
+		return qrcode.Metadata{}, err
+	}
+	return m.Metadata(), nil
+}
+
+// QRCodeMatrix retorna a matriz de módulos do QR Code do payload. Ela é gerada uma única vez
+// e usada por todos os renderizadores (PNG, SVG, PDF e ASCII), que assim produzem o mesmo
+// símbolo; também permite escrever renderizadores próprios.
+func (p *Pix) QRCodeMatrix() (*qrcode.Matrix, error) {
+	if p.params.GetQRCodeContent() == "" {
+		if _, err := p.GenPayload(); err != nil {
+			return nil, err
+		}
+	}
+	content, level := p.params.GetQRCodeContent(), p.qrLevel()
+	if level == 0 {
+		level = qrcode.Medium
+	}
+	if p.matrix == nil || p.matrix.Content() != content || p.matrix.Level() != level {
+		m, err := qrcode.Encode(content, level)
+		if err != nil {
+			return nil, err
+		}
+		p.matrix = m
+	}
+	return p.matrix, nil
+}
+
+// qrLevel retorna o nível de correção efetivo: com logo o símbolo é sempre gerado em High
+func (p *Pix) qrLevel() qrcode.ErrorCorrectionLevel {
+	if p.params.GetQRCodeLogo() != nil {
+		return qrcode.High
+	}
+	return p.params.GetQRCodeErrorCorrection()
+}
+
 // -------- Helpers --------
 
 // tlv gera um campo TLV formatado
@@ -224,10 +366,11 @@ func (p *Pix) generateAdditionalData() (string, error) {
 
 // replaceCRC calcula e substitui o CRC16 corretamente
 func (p *Pix) replaceCRC(payload string) string {
-	re := regexp.MustCompile(`\w{4}$`)
-	payload = sanitizePayload(re.ReplaceAllString(payload, ""))
-	crcValue := fmt.Sprintf("%04X", crc.CalculateCRC(crc.CCITT, []byte(payload)))
-	return payload + crcValue
+	if len(payload) >= 4 {
+		payload = payload[:len(payload)-4]
+	}
+	payload = sanitizePayload(payload)
+	return payload + ComputeCRC(payload)
 }
 
 // -------- Normalização --------
@@ -1,8 +1,14 @@
 package pix
 
 import (
+	"bytes"
+	"image/color"
+	"image/png"
 	"strings"
 	"testing"
+
+	"github.com/thiagozs/go-pixgen/assets"
+	"github.com/thiagozs/go-pixgen/qrcode"
 )
 
 func TestBacenConformance(t *testing.T) {
@@ -243,3 +249,327 @@ func TestGenQRCodeASCII(t *testing.T) {
 		t.Fatalf("expected multiline ascii qrcode, got %q", ascii)
 	}
 }
+
+func TestGenQRCodeSVG(t *testing.T) {
+	opts := []Options{
+		OptPixKey("11999887766"),
+		OptMerchantName("FULANO DE TAL"),
+		OptMerchantCity("SAO PAULO"),
+		OptAmount("10.00"),
+		OptSVGModuleSize(4),
+		OptQRCodeQuietZone(0),
+	}
+
+	p, err := New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating pix: %v", err)
+	}
+
+	svg, err := p.GenQRCodeSVG()
+	if err != nil {
+		t.Fatalf("generate svg qrcode: %v", err)
+	}
+	if !strings.Contains(string(svg), "<svg") || !strings.Contains(string(svg), `viewBox="0 0 `) {
+		t.Fatalf("unexpected svg output: %s", svg)
+	}
+	if _, err := New(append(opts, OptSVGModuleSize(0))...); err == nil {
+		t.Fatalf("expected error for invalid module size")
+	}
+}
+
+func TestGenQRCodePDF(t *testing.T) {
+	opts := []Options{
+		OptPixKey("11999887766"),
+		OptMerchantName("FULANO DE TAL"),
+		OptMerchantCity("SAO PAULO"),
+	}
+
+	p, err := New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating pix: %v", err)
+	}
+
+	pdf, err := p.GenQRCodePDF(qrcode.PDFOptions{SizeMM: 30, BleedMM: 2, CropMarks: true})
+	if err != nil {
+		t.Fatalf("generate pdf qrcode: %v", err)
+	}
+	if !strings.HasPrefix(string(pdf), "%PDF-") {
+		t.Fatalf("expected pdf document")
+	}
+}
+
+func TestQRCodeErrorCorrection(t *testing.T) {
+	opts := []Options{
+		OptPixKey("11999887766"),
+		OptMerchantName("FULANO DE TAL"),
+		OptMerchantCity("SAO PAULO"),
+	}
+
+	low, err := New(append(opts, OptQRCodeErrorCorrection(qrcode.Low))...)
+	if err != nil {
+		t.Fatalf("unexpected error creating pix: %v", err)
+	}
+	high, err := New(append(opts, OptQRCodeErrorCorrection(qrcode.High))...)
+	if err != nil {
+		t.Fatalf("unexpected error creating pix: %v", err)
+	}
+
+	lowMeta, err := low.QRCodeMetadata()
+	if err != nil {
+		t.Fatalf("metadata: %v", err)
+	}
+	highMeta, err := high.QRCodeMetadata()
+	if err != nil {
+		t.Fatalf("metadata: %v", err)
+	}
+	if lowMeta.Level != qrcode.Low || highMeta.Level != qrcode.High {
+		t.Fatalf("unexpected levels: %s %s", lowMeta.Level, highMeta.Level)
+	}
+	if lowMeta.Version >= highMeta.Version {
+		t.Fatalf("expected level H to require a larger version")
+	}
+
+	if _, err := high.GenQRCode(); err != nil {
+		t.Fatalf("generate qrcode: %v", err)
+	}
+	if _, err := New(append(opts, OptQRCodeErrorCorrection(qrcode.ErrorCorrectionLevel(7)))...); err == nil {
+		t.Fatalf("expected error for invalid level")
+	}
+}
+
+func TestQRCodeColorsOptions(t *testing.T) {
+	opts := []Options{
+		OptPixKey("11999887766"),
+		OptMerchantName("FULANO DE TAL"),
+		OptMerchantCity("SAO PAULO"),
+	}
+
+	navy := color.NRGBA{R: 0x1a, G: 0x23, B: 0x7e, A: 0xff}
+	p, err := New(append(opts, OptQRCodeColors(navy, nil), OptQRCodeTransparentBackground(true), OptQRCodeQuietZone(0))...)
+	if err != nil {
+		t.Fatalf("unexpected error creating pix: %v", err)
+	}
+	data, err := p.GenQRCode()
+	if err != nil {
+		t.Fatalf("generate qrcode: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	if c := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA); c != navy {
+		t.Fatalf("expected navy finder pattern at origin without border, got %+v", c)
+	}
+
+	if _, err := New(append(opts, OptQRCodeColors(color.White, color.Black))...); err == nil {
+		t.Fatalf("expected low contrast colors to be rejected")
+	}
+}
+
+func TestQRCodeLogoOption(t *testing.T) {
+	p, err := New(
+		OptPixKey("11999887766"),
+		OptMerchantName("FULANO DE TAL"),
+		OptMerchantCity("SAO PAULO"),
+		OptQRCodeErrorCorrection(qrcode.Low),
+		OptQRCodeLogo(qrcode.Logo{Data: assets.PixLogo}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating pix: %v", err)
+	}
+	meta, err := p.QRCodeMetadata()
+	if err != nil {
+		t.Fatalf("metadata: %v", err)
+	}
+	if meta.Level != qrcode.High {
+		t.Fatalf("expected logo to force level H, got %s", meta.Level)
+	}
+	if _, err := p.GenQRCode(); err != nil {
+		t.Fatalf("generate qrcode: %v", err)
+	}
+	if _, err := p.GenQRCodeSVG(); err != nil {
+		t.Fatalf("generate svg: %v", err)
+	}
+
+	if _, err := New(OptQRCodeLogo(qrcode.Logo{})); err == nil {
+		t.Fatalf("expected empty logo to be rejected")
+	}
+}
+
+func TestQRCodeMatrix(t *testing.T) {
+	p, err := New(
+		OptPixKey("11999887766"),
+		OptMerchantName("FULANO DE TAL"),
+		OptMerchantCity("SAO PAULO"),
+		OptASCIICharset("#", "."),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating pix: %v", err)
+	}
+	m, err := p.QRCodeMatrix()
+	if err != nil {
+		t.Fatalf("matrix: %v", err)
+	}
+	again, _ := p.QRCodeMatrix()
+	if m != again {
+		t.Fatalf("expected the matrix to be generated once")
+	}
+	payload, _ := p.GenPayload()
+	if m.Content() != payload {
+		t.Fatalf("matrix content differs from payload")
+	}
+	// the metadata comes from the same matrix instead of a second encode
+	meta, err := p.QRCodeMetadata()
+	if err != nil {
+		t.Fatalf("metadata: %v", err)
+	}
+	if again, _ := p.QRCodeMatrix(); again != m || meta != m.Metadata() || meta.Modules != m.Size() {
+		t.Fatalf("expected the metadata of the cached matrix, got %+v", meta)
+	}
+
+	ascii, err := p.GenQRCodeASCII()
+	if err != nil {
+		t.Fatalf("generate ascii qrcode: %v", err)
+	}
+	framed, _ := m.WithQuietZone(1)
+	for y, row := range strings.Split(ascii, "\n") {
+		for x, c := range row {
+			if (c == '#') != framed.Get(x, y) {
+				t.Fatalf("ascii module %d,%d differs from the matrix", x, y)
+			}
+		}
+	}
+}
+
+func TestGenQRCodeHalfBlock(t *testing.T) {
+	p, err := New(
+		OptPixKey("11999887766"),
+		OptMerchantName("FULANO DE TAL"),
+		OptMerchantCity("SAO PAULO"),
+		OptASCIIHalfBlock(false),
+		OptASCIITerminalWidth(80),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating pix: %v", err)
+	}
+	out, err := p.GenQRCodeASCII()
+	if err != nil {
+		t.Fatalf("generate half-block qrcode: %v", err)
+	}
+	if !strings.ContainsAny(out, "▀▄█") {
+		t.Fatalf("expected half-block characters, got %q", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if n := len([]rune(line)); n > 80 {
+			t.Fatalf("line with %d columns exceeds the terminal width", n)
+		}
+	}
+}
+
+func TestGenQRCodeTerminalImages(t *testing.T) {
+	p, err := New(
+		OptPixKey("11999887766"),
+		OptMerchantName("FULANO DE TAL"),
+		OptMerchantCity("SAO PAULO"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating pix: %v", err)
+	}
+	sixel, err := p.GenQRCodeSixel()
+	if err != nil {
+		t.Fatalf("generate sixel: %v", err)
+	}
+	if !strings.HasPrefix(sixel, "\x1bP") {
+		t.Fatalf("expected sixel escape sequence")
+	}
+	kitty, err := p.GenQRCodeKitty()
+	if err != nil {
+		t.Fatalf("generate kitty: %v", err)
+	}
+	if !strings.HasPrefix(kitty, "\x1b_G") {
+		t.Fatalf("expected kitty graphics escape sequence")
+	}
+}
+
+func TestQRCodeCacheOption(t *testing.T) {
+	cache, err := qrcode.NewCache(8)
+	if err != nil {
+		t.Fatalf("new cache: %v", err)
+	}
+	opts := []Options{
+		OptPixKey("11999887766"),
+		OptMerchantName("FULANO DE TAL"),
+		OptMerchantCity("SAO PAULO"),
+		OptAmount("10.00"),
+	}
+
+	var first []byte
+	for i := 0; i < 3; i++ {
+		p, err := New(append(opts, OptQRCodeCache(cache))...)
+		if err != nil {
+			t.Fatalf("unexpected error creating pix: %v", err)
+		}
+		data, err := p.GenQRCode()
+		if err != nil {
+			t.Fatalf("generate qrcode: %v", err)
+		}
+		if first == nil {
+			first = data
+		}
+	}
+	if s := cache.Stats(); s.Misses != 1 || s.Hits != 2 {
+		t.Fatalf("expected one render and two hits, got %+v", s)
+	}
+
+	p, _ := New(opts...)
+	uncached, err := p.GenQRCode()
+	if err != nil {
+		t.Fatalf("generate qrcode: %v", err)
+	}
+	if !bytes.Equal(first, uncached) {
+		t.Fatalf("cached qrcode differs from the uncached render")
+	}
+
+	// the metadata of a cached charge is cached too
+	for i := 0; i < 2; i++ {
+		cached, _ := New(append(opts, OptQRCodeCache(cache))...)
+		meta, err := cached.QRCodeMetadata()
+		if err != nil {
+			t.Fatalf("metadata: %v", err)
+		}
+		if want, _ := p.QRCodeMetadata(); meta != want {
+			t.Fatalf("cached metadata %+v differs from %+v", meta, want)
+		}
+	}
+	if s := cache.Stats(); s.Misses != 2 || s.Hits != 3 {
+		t.Fatalf("expected the metadata to be computed once, got %+v", s)
+	}
+}
+
+func BenchmarkGenQRCode(b *testing.B) {
+	cache, _ := qrcode.NewCache(0)
+	for _, bc := range []struct {
+		name string
+		opts []Options
+	}{
+		{"uncached", nil},
+		{"cached", []Options{OptQRCodeCache(cache)}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p, err := New(append([]Options{
+					OptPixKey("11999887766"),
+					OptMerchantName("FULANO DE TAL"),
+					OptMerchantCity("SAO PAULO"),
+					OptAmount("10.00"),
+				}, bc.opts...)...)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := p.GenQRCode(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
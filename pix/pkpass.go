@@ -0,0 +1,314 @@
+package pix
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/image/draw"
+
+	"github.com/thiagozs/go-pixgen/assets"
+	"github.com/thiagozs/go-pixgen/internal/pkcs7"
+)
+
+// PKPassOptions configures the Apple Wallet pass built by NewPKPass.
+type PKPassOptions struct {
+	// PassTypeID and TeamID identify the pass type registered in the Apple Developer
+	// account ("pass.com.example.pix" and the 10 character team ID).
+	PassTypeID string
+	TeamID     string
+	// SerialNumber defaults to the TxID, or a random identifier for "***" payloads.
+	SerialNumber string
+	// OrganizationName defaults to the merchant name; Description to "Cobrança Pix".
+	OrganizationName string
+	Description      string
+	// ExpiresAt sets the pass expirationDate and adds a due date field when set.
+	ExpiresAt *time.Time
+	// Certificate and PrivateKey are the Pass Type ID certificate (see
+	// ParsePKPassCertificate); Intermediates holds the Apple WWDR certificate.
+	Certificate   *x509.Certificate
+	PrivateKey    crypto.Signer
+	Intermediates []*x509.Certificate
+	// Images adds or replaces pass images by file name ("icon.png", "logo@2x.png",
+	// "strip.png"...); icon and logo default to the Pix logo.
+	Images map[string][]byte
+	// BackgroundColor, ForegroundColor and LabelColor default to the card colors.
+	BackgroundColor color.Color
+	ForegroundColor color.Color
+	LabelColor      color.Color
+	// SigningTime defaults to the current time.
+	SigningTime time.Time
+}
+
+type pkpassBarcode struct {
+	Format          string `json:"format"`
+	Message         string `json:"message"`
+	MessageEncoding string `json:"messageEncoding"`
+	AltText         string `json:"altText,omitempty"`
+}
+
+type pkpassField struct {
+	Key       string `json:"key"`
+	Label     string `json:"label,omitempty"`
+	Value     string `json:"value"`
+	DateStyle string `json:"dateStyle,omitempty"`
+	TimeStyle string `json:"timeStyle,omitempty"`
+}
+
+type pkpassStructure struct {
+	PrimaryFields   []pkpassField `json:"primaryFields,omitempty"`
+	SecondaryFields []pkpassField `json:"secondaryFields,omitempty"`
+	AuxiliaryFields []pkpassField `json:"auxiliaryFields,omitempty"`
+	BackFields      []pkpassField `json:"backFields,omitempty"`
+}
+
+type pkpassJSON struct {
+	FormatVersion      int             `json:"formatVersion"`
+	PassTypeIdentifier string          `json:"passTypeIdentifier"`
+	SerialNumber       string          `json:"serialNumber"`
+	TeamIdentifier     string          `json:"teamIdentifier"`
+	OrganizationName   string          `json:"organizationName"`
+	Description        string          `json:"description"`
+	LogoText           string          `json:"logoText,omitempty"`
+	BackgroundColor    string          `json:"backgroundColor"`
+	ForegroundColor    string          `json:"foregroundColor"`
+	LabelColor         string          `json:"labelColor"`
+	ExpirationDate     string          `json:"expirationDate,omitempty"`
+	Barcodes           []pkpassBarcode `json:"barcodes"`
+	// Barcode is the pre-iOS 9 key, still read by some Android wallet apps.
+	Barcode pkpassBarcode   `json:"barcode"`
+	Generic pkpassStructure `json:"generic"`
+}
+
+// pkpassReserved are the files NewPKPass writes itself.
+var pkpassReserved = map[string]bool{"pass.json": true, "manifest.json": true, "signature": true}
+
+// NewPKPass builds a signed Apple Wallet pass (.pkpass) for parsed: a generic pass with the
+// amount, merchant, TxID and optional due date, a QR barcode carrying the payload and the
+// copia-e-cola on the back. The archive holds pass.json, the images, manifest.json (SHA-1
+// of every file) and signature, a detached PKCS#7 signature of the manifest.
+func NewPKPass(parsed *ParsedPayload, opts PKPassOptions) ([]byte, error) {
+	if parsed == nil || parsed.Raw == "" {
+		return nil, errors.New("pix: pkpass requires a parsed payload")
+	}
+	if opts.PassTypeID == "" || opts.TeamID == "" {
+		return nil, errors.New("pix: pkpass requires the pass type identifier and team identifier")
+	}
+	if opts.Certificate == nil || opts.PrivateKey == nil {
+		return nil, errors.New("pix: pkpass requires the pass certificate and private key")
+	}
+
+	pass, err := pkpassDocument(parsed, opts)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{"pass.json": pass}
+
+	if opts.Images["icon.png"] == nil || opts.Images["logo.png"] == nil {
+		defaults, err := pkpassDefaultImages()
+		if err != nil {
+			return nil, err
+		}
+		for name, data := range defaults {
+			files[name] = data
+		}
+	}
+	for name, data := range opts.Images {
+		if pkpassReserved[name] || strings.ContainsAny(name, `/\`) || !strings.HasSuffix(name, ".png") {
+			return nil, fmt.Errorf("pix: invalid pkpass image name %q", name)
+		}
+		files[name] = data
+	}
+
+	names := make([]string, 0, len(files))
+	manifest := make(map[string]string, len(files))
+	for name, data := range files {
+		sum := sha1.Sum(data)
+		manifest[name] = hex.EncodeToString(sum[:])
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("pix: encode pkpass manifest: %w", err)
+	}
+
+	signingTime := opts.SigningTime
+	if signingTime.IsZero() {
+		signingTime = time.Now()
+	}
+	signature, err := pkcs7.SignDetached(manifestJSON, opts.Certificate, opts.PrivateKey, opts.Intermediates, signingTime)
+	if err != nil {
+		return nil, fmt.Errorf("pix: sign pkpass: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	add := func(name string, data []byte) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: signingTime})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	for _, name := range names {
+		if err := add(name, files[name]); err != nil {
+			return nil, fmt.Errorf("pix: write pkpass: %w", err)
+		}
+	}
+	if err := add("manifest.json", manifestJSON); err != nil {
+		return nil, fmt.Errorf("pix: write pkpass: %w", err)
+	}
+	if err := add("signature", signature); err != nil {
+		return nil, fmt.Errorf("pix: write pkpass: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("pix: write pkpass: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ParsePKPassCertificate parses the PEM encoded Pass Type ID certificate and its private
+// key (PKCS#1, PKCS#8 or EC), as exported from the .p12 with
+// "openssl pkcs12 -in pass.p12 -clcerts -nokeys" and "-nocerts -nodes".
+func ParsePKPassCertificate(certPEM, keyPEM []byte) (*x509.Certificate, crypto.Signer, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pix: pkpass certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("pix: pkpass certificate: %w", err)
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, errors.New("pix: pkpass private key cannot sign")
+	}
+	return cert, key, nil
+}
+
+func pkpassDocument(parsed *ParsedPayload, opts PKPassOptions) ([]byte, error) {
+	serial := opts.SerialNumber
+	if serial == "" {
+		serial = parsed.AdditionalDataField.TxID
+	}
+	if serial == "" || serial == "***" {
+		var b [8]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return nil, fmt.Errorf("pix: pkpass serial number: %w", err)
+		}
+		serial = hex.EncodeToString(b[:])
+	}
+	organization := opts.OrganizationName
+	if organization == "" {
+		organization = parsed.MerchantName
+	}
+	description := opts.Description
+	if description == "" {
+		description = "Cobrança Pix"
+	}
+
+	barcode := pkpassBarcode{
+		Format:          "PKBarcodeFormatQR",
+		Message:         parsed.Raw,
+		MessageEncoding: "iso-8859-1",
+		AltText:         "Pix Copia e Cola",
+	}
+	pass := pkpassJSON{
+		FormatVersion:      1,
+		PassTypeIdentifier: opts.PassTypeID,
+		SerialNumber:       serial,
+		TeamIdentifier:     opts.TeamID,
+		OrganizationName:   organization,
+		Description:        description,
+		LogoText:           parsed.MerchantName,
+		BackgroundColor:    pkpassColor(opts.BackgroundColor, cardAccent),
+		ForegroundColor:    pkpassColor(opts.ForegroundColor, color.White),
+		LabelColor:         pkpassColor(opts.LabelColor, color.NRGBA{R: 0xd9, G: 0xf2, B: 0xef, A: 0xff}),
+		Barcodes:           []pkpassBarcode{barcode},
+		Barcode:            barcode,
+	}
+
+	amount := "Valor livre"
+	if parsed.TransactionAmount != "" {
+		amount = formatBRL(parsed.TransactionAmount)
+	}
+	g := &pass.Generic
+	g.PrimaryFields = []pkpassField{{Key: "amount", Label: "VALOR", Value: amount}}
+	g.SecondaryFields = []pkpassField{{Key: "merchant", Label: "RECEBEDOR", Value: parsed.MerchantName}}
+	if parsed.MerchantCity != "" {
+		g.SecondaryFields = append(g.SecondaryFields, pkpassField{Key: "city", Label: "CIDADE", Value: parsed.MerchantCity})
+	}
+	if txid := parsed.AdditionalDataField.TxID; txid != "" && txid != "***" {
+		g.AuxiliaryFields = append(g.AuxiliaryFields, pkpassField{Key: "txid", Label: "TXID", Value: txid})
+	}
+	if opts.ExpiresAt != nil {
+		expires := opts.ExpiresAt.Format(time.RFC3339)
+		pass.ExpirationDate = expires
+		g.AuxiliaryFields = append(g.AuxiliaryFields, pkpassField{
+			Key: "expires", Label: "VENCIMENTO", Value: expires,
+			DateStyle: "PKDateStyleShort", TimeStyle: "PKDateStyleShort",
+		})
+	}
+	g.BackFields = []pkpassField{{Key: "payload", Label: "Pix Copia e Cola", Value: parsed.Raw}}
+
+	data, err := json.MarshalIndent(pass, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("pix: encode pass.json: %w", err)
+	}
+	return data, nil
+}
+
+// pkpassDefaultImages renders the Pix logo at the icon (29pt) and logo (50pt) sizes, at 1x,
+// 2x and 3x.
+func pkpassDefaultImages() (map[string][]byte, error) {
+	logo, err := png.Decode(bytes.NewReader(assets.PixLogo))
+	if err != nil {
+		return nil, fmt.Errorf("pix: decode pix logo: %w", err)
+	}
+	images := make(map[string][]byte, 6)
+	for _, base := range []struct {
+		name string
+		side int
+	}{{"icon", 29}, {"logo", 50}} {
+		for scale := 1; scale <= 3; scale++ {
+			side := base.side * scale
+			dst := image.NewNRGBA(image.Rect(0, 0, side, side))
+			draw.CatmullRom.Scale(dst, dst.Bounds(), logo, logo.Bounds(), draw.Src, nil)
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, dst); err != nil {
+				return nil, fmt.Errorf("pix: encode pkpass image: %w", err)
+			}
+			name := base.name + ".png"
+			if scale > 1 {
+				name = fmt.Sprintf("%s@%dx.png", base.name, scale)
+			}
+			images[name] = buf.Bytes()
+		}
+	}
+	return images, nil
+}
+
+// pkpassColor formats c as the "rgb(r, g, b)" strings of pass.json.
+func pkpassColor(c, fallback color.Color) string {
+	if c == nil {
+		c = fallback
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("rgb(%d, %d, %d)", n.R, n.G, n.B)
+}
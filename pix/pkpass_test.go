@@ -0,0 +1,172 @@
+package pix
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/thiagozs/go-pixgen/internal/pkcs7"
+)
+
+func testPassCertificate(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(7),
+		Subject:      pkix.Name{CommonName: "Pass Type ID: pass.com.example.pix"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestNewPKPass(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	certPEM, keyPEM := testPassCertificate(t)
+	cert, key, err := ParsePKPassCertificate(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	expires := time.Date(2030, 1, 2, 15, 4, 0, 0, time.UTC)
+	strip := []byte("custom strip image")
+
+	pass, err := NewPKPass(parsed, PKPassOptions{
+		PassTypeID:  "pass.com.example.pix",
+		TeamID:      "ABCDE12345",
+		Certificate: cert,
+		PrivateKey:  key,
+		ExpiresAt:   &expires,
+		Images:      map[string][]byte{"strip.png": strip},
+	})
+	if err != nil {
+		t.Fatalf("new pkpass: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(pass), int64(len(pass)))
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+		files[f.Name] = data
+	}
+	for _, name := range []string{"pass.json", "manifest.json", "signature", "icon.png", "icon@2x.png", "icon@3x.png", "logo.png", "logo@2x.png", "strip.png"} {
+		if files[name] == nil {
+			t.Fatalf("expected %s in the archive", name)
+		}
+	}
+	if !bytes.Equal(files["strip.png"], strip) {
+		t.Fatalf("expected the custom image to be kept as is")
+	}
+
+	var manifest map[string]string
+	if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	if len(manifest) != len(files)-2 {
+		t.Fatalf("expected every file but manifest and signature in the manifest, got %v", manifest)
+	}
+	for name, hash := range manifest {
+		sum := sha1.Sum(files[name])
+		if hex.EncodeToString(sum[:]) != hash {
+			t.Fatalf("manifest hash mismatch for %s", name)
+		}
+	}
+	signer, err := pkcs7.VerifyDetached(files["signature"], files["manifest.json"])
+	if err != nil {
+		t.Fatalf("verify signature: %v", err)
+	}
+	if !signer.Equal(cert) {
+		t.Fatalf("unexpected signer %s", signer.Subject)
+	}
+
+	var doc pkpassJSON
+	if err := json.Unmarshal(files["pass.json"], &doc); err != nil {
+		t.Fatalf("decode pass.json: %v", err)
+	}
+	if doc.FormatVersion != 1 || doc.PassTypeIdentifier != "pass.com.example.pix" || doc.TeamIdentifier != "ABCDE12345" {
+		t.Fatalf("unexpected pass header %+v", doc)
+	}
+	if len(doc.SerialNumber) != 16 {
+		t.Fatalf("expected a random serial number for the *** txid, got %q", doc.SerialNumber)
+	}
+	if len(doc.Barcodes) != 1 || doc.Barcodes[0].Format != "PKBarcodeFormatQR" || doc.Barcodes[0].Message != bacenSamplePayload {
+		t.Fatalf("unexpected barcodes %+v", doc.Barcodes)
+	}
+	if doc.ExpirationDate != "2030-01-02T15:04:00Z" {
+		t.Fatalf("unexpected expiration date %q", doc.ExpirationDate)
+	}
+	if got := doc.Generic.PrimaryFields[0].Value; got != "Valor livre" {
+		t.Fatalf("unexpected amount field %q", got)
+	}
+	if got := doc.Generic.BackFields[0].Value; got != bacenSamplePayload {
+		t.Fatalf("unexpected back field %q", got)
+	}
+}
+
+func TestNewPKPassErrors(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	certPEM, keyPEM := testPassCertificate(t)
+	cert, key, err := ParsePKPassCertificate(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	valid := PKPassOptions{PassTypeID: "pass.com.example.pix", TeamID: "ABCDE12345", Certificate: cert, PrivateKey: key}
+
+	for name, opts := range map[string]PKPassOptions{
+		"pass type":     {TeamID: "ABCDE12345", Certificate: cert, PrivateKey: key},
+		"certificate":   {PassTypeID: "pass.com.example.pix", TeamID: "ABCDE12345"},
+		"reserved name": {PassTypeID: valid.PassTypeID, TeamID: valid.TeamID, Certificate: cert, PrivateKey: key, Images: map[string][]byte{"pass.json": nil}},
+		"path":          {PassTypeID: valid.PassTypeID, TeamID: valid.TeamID, Certificate: cert, PrivateKey: key, Images: map[string][]byte{"../icon.png": nil}},
+	} {
+		if _, err := NewPKPass(parsed, opts); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+	if _, err := NewPKPass(nil, valid); err == nil {
+		t.Fatalf("expected a nil payload to be rejected")
+	}
+	if _, _, err := ParsePKPassCertificate(certPEM, []byte("not a key")); err == nil {
+		t.Fatalf("expected an invalid key to be rejected")
+	}
+}
@@ -0,0 +1,137 @@
+package pix
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile holds the merchant data shared by every payload of a receiver, so services can
+// keep it in configuration instead of repeating the functional options.
+type Profile struct {
+	Name           string `json:"name" yaml:"name"`
+	Kind           string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	PixKey         string `json:"pixKey,omitempty" yaml:"pixKey,omitempty"`
+	URL            string `json:"url,omitempty" yaml:"url,omitempty"`
+	MerchantName   string `json:"merchantName" yaml:"merchantName"`
+	MerchantCity   string `json:"merchantCity" yaml:"merchantCity"`
+	Description    string `json:"description,omitempty" yaml:"description,omitempty"`
+	AdditionalInfo string `json:"additionalInfo,omitempty" yaml:"additionalInfo,omitempty"`
+}
+
+// Profiles is a named collection of merchant profiles.
+type Profiles []Profile
+
+type profilesFile struct {
+	Profiles Profiles `json:"profiles" yaml:"profiles"`
+}
+
+// Get returns the profile with the given name (case-insensitive).
+func (ps Profiles) Get(name string) (Profile, bool) {
+	name = strings.TrimSpace(name)
+	for _, p := range ps {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// LoadProfiles reads a multi-merchant profiles document in JSON or YAML. The document can be
+// either a list of profiles or an object with a "profiles" list; a document without any
+// profile is an error.
+func LoadProfiles(r io.Reader) (Profiles, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read profiles: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("profiles document must not be empty")
+	}
+
+	var profiles Profiles
+	if looksLikeJSON(string(data)) {
+		profiles, err = decodeProfiles(data, json.Unmarshal)
+	} else {
+		profiles, err = decodeProfiles(data, yaml.Unmarshal)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, errors.New(`profiles document has no profiles (expected a list or a "profiles" key)`)
+	}
+
+	seen := make(map[string]bool, len(profiles))
+	for i, p := range profiles {
+		name := strings.ToLower(strings.TrimSpace(p.Name))
+		if name == "" {
+			return nil, fmt.Errorf("profile %d must have a name", i)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicated profile %q", p.Name)
+		}
+		seen[name] = true
+	}
+
+	return profiles, nil
+}
+
+func decodeProfiles(data []byte, unmarshal func([]byte, interface{}) error) (Profiles, error) {
+	if data[0] == '[' || data[0] == '-' {
+		var list Profiles
+		if err := unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("decode profiles: %w", err)
+		}
+		return list, nil
+	}
+
+	var doc profilesFile
+	if err := unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode profiles: %w", err)
+	}
+	return doc.Profiles, nil
+}
+
+// Options converts the profile into functional options.
+func (pr Profile) Options() ([]Options, error) {
+	kind, err := ParseKind(pr.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []Options{
+		OptKind(kind),
+		OptMerchantName(pr.MerchantName),
+		OptMerchantCity(pr.MerchantCity),
+	}
+	if pr.PixKey != "" {
+		opts = append(opts, OptPixKey(pr.PixKey))
+	}
+	if pr.URL != "" {
+		opts = append(opts, OptUrl(pr.URL))
+	}
+	if pr.Description != "" {
+		opts = append(opts, OptDescription(pr.Description))
+	}
+	if pr.AdditionalInfo != "" {
+		opts = append(opts, OptAdditionalInfo(pr.AdditionalInfo))
+	}
+	return opts, nil
+}
+
+// NewFromProfile creates a Pix from a merchant profile. Extra options are applied after the
+// profile values, so they can set per-charge fields (amount, TxID) or override the profile.
+func NewFromProfile(profile Profile, opts ...Options) (*Pix, error) {
+	base, err := profile.Options()
+	if err != nil {
+		return nil, err
+	}
+	return New(append(base, opts...)...)
+}
@@ -0,0 +1,105 @@
+package pix
+
+import (
+	"strings"
+	"testing"
+)
+
+const yamlProfiles = `
+profiles:
+  - name: loja-centro
+    pixKey: "11999887766"
+    merchantName: Loja Centro
+    merchantCity: CURITIBA
+    additionalInfo: Obrigado pela compra
+  - name: loja-online
+    kind: dynamic
+    url: https://example.com/pix/cob
+    merchantName: Loja Online
+    merchantCity: SAO PAULO
+`
+
+const jsonProfiles = `[
+  {"name": "loja-centro", "pixKey": "11999887766", "merchantName": "Loja Centro", "merchantCity": "CURITIBA"}
+]`
+
+func TestLoadProfilesYAML(t *testing.T) {
+	profiles, err := LoadProfiles(strings.NewReader(yamlProfiles))
+	if err != nil {
+		t.Fatalf("load profiles: %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("expected 2 profiles, got %d", len(profiles))
+	}
+
+	online, ok := profiles.Get("LOJA-ONLINE")
+	if !ok {
+		t.Fatalf("expected to find profile loja-online")
+	}
+	if online.Kind != "dynamic" || online.URL != "https://example.com/pix/cob" {
+		t.Fatalf("unexpected profile: %+v", online)
+	}
+	if _, ok := profiles.Get("missing"); ok {
+		t.Fatalf("unexpected profile found")
+	}
+}
+
+func TestLoadProfilesJSON(t *testing.T) {
+	profiles, err := LoadProfiles(strings.NewReader(jsonProfiles))
+	if err != nil {
+		t.Fatalf("load profiles: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].MerchantCity != "CURITIBA" {
+		t.Fatalf("unexpected profiles: %+v", profiles)
+	}
+}
+
+func TestLoadProfilesErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty":      "",
+		"no name":    `[{"merchantName": "Loja"}]`,
+		"duplicated": `[{"name": "a"}, {"name": "A"}]`,
+		"invalid":    `{"profiles": [`,
+		"wrong key":  `{"merchants": [{"name": "a"}]}`,
+		"empty list": `[]`,
+		"yaml key":   "merchants:\n  - name: a\n",
+	}
+
+	for name, doc := range tests {
+		if _, err := LoadProfiles(strings.NewReader(doc)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestNewFromProfile(t *testing.T) {
+	profiles, err := LoadProfiles(strings.NewReader(yamlProfiles))
+	if err != nil {
+		t.Fatalf("load profiles: %v", err)
+	}
+	profile, _ := profiles.Get("loja-centro")
+
+	p, err := NewFromProfile(profile, OptAmount("10.00"), OptTxId("PEDIDO1"))
+	if err != nil {
+		t.Fatalf("new from profile: %v", err)
+	}
+
+	payload, err := p.GenPayload()
+	if err != nil {
+		t.Fatalf("generate payload: %v", err)
+	}
+	parsed, err := ParsePayload(payload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	if parsed.MerchantName != "LOJA CENTRO" || parsed.TransactionAmount != "10.00" {
+		t.Fatalf("unexpected parsed payload: %+v", parsed)
+	}
+	if parsed.MerchantAccounts[0].PixKey != "+5511999887766" {
+		t.Fatalf("unexpected pix key %s", parsed.MerchantAccounts[0].PixKey)
+	}
+
+	if _, err := NewFromProfile(Profile{Name: "x", Kind: "other"}); err == nil {
+		t.Fatalf("expected invalid kind error")
+	}
+}
@@ -0,0 +1,131 @@
+package pix
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+//go:generate go run ../internal/schemagen -dir ../docs/schema
+
+const schemaBaseURL = "https://github.com/thiagozs/go-pixgen/docs/schema/"
+
+// schemaWireTypes maps the public types with custom JSON encoding to their wire structs.
+var schemaWireTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(ParsedPayload{}):  reflect.TypeOf(parsedPayloadJSON{}),
+	reflect.TypeOf(DynamicPayload{}): reflect.TypeOf(dynamicPayloadJSON{}),
+}
+
+// ParsedPayloadSchema returns the JSON Schema (draft 2020-12) of the ParsedPayload JSON form.
+func ParsedPayloadSchema() ([]byte, error) {
+	return buildSchema("parsed-payload", reflect.TypeOf(ParsedPayload{}))
+}
+
+// DynamicPayloadSchema returns the JSON Schema (draft 2020-12) of the DynamicPayload JSON form.
+func DynamicPayloadSchema() ([]byte, error) {
+	return buildSchema("dynamic-payload", reflect.TypeOf(DynamicPayload{}))
+}
+
+type schemaBuilder struct {
+	defs map[string]interface{}
+}
+
+func buildSchema(id string, root reflect.Type) ([]byte, error) {
+	b := &schemaBuilder{defs: make(map[string]interface{})}
+	schema := b.object(root)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["$id"] = schemaBaseURL + id + ".schema.json"
+	schema["title"] = root.Name()
+	delete(b.defs, root.Name())
+	if len(b.defs) > 0 {
+		schema["$defs"] = b.defs
+	}
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func (b *schemaBuilder) typeSchema(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == reflect.TypeOf(json.Number("")):
+		return map[string]interface{}{"type": "number"}
+	case t == reflect.TypeOf(time.Time{}):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": b.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.typeSchema(t.Elem())}
+	case reflect.Struct:
+		name := t.Name()
+		if _, seen := b.defs[name]; !seen {
+			// placeholder breaks recursion for self-referencing types such as TLV
+			b.defs[name] = nil
+			b.defs[name] = b.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func (b *schemaBuilder) object(t reflect.Type) map[string]interface{} {
+	if wire, ok := schemaWireTypes[t]; ok {
+		t = wire
+	}
+
+	properties := make(map[string]interface{})
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, opts := field.Name, ""
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			name, opts = splitJSONTag(tag)
+			if name == "" {
+				name = field.Name
+			}
+		}
+		properties[name] = b.typeSchema(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+func splitJSONTag(tag string) (string, string) {
+	if i := strings.Index(tag, ","); i >= 0 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
@@ -0,0 +1,140 @@
+package pix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Template is a static Pix payload compiled once from the merchant options. Only the
+// transaction amount (tag 54) and the TxID (tag 62/05) vary between rendered payloads,
+// so rendering skips option validation, regular expressions and tag rebuilding.
+// A Template is immutable and safe for concurrent use.
+type Template struct {
+	head     string // tags 00, 01, 26, 52 and 53
+	merchant string // tags 58, 59 and 60
+}
+
+// NewTemplate validates the merchant options and precompiles the fixed parts of the payload.
+// Amount and TxID options, when present, are ignored: they are provided on each render.
+func NewTemplate(opts ...Options) (*Template, error) {
+	p, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if p.params.GetKind() != STATIC {
+		return nil, errors.New("templates are supported only for static pix")
+	}
+
+	mai, err := p.generateMAI()
+	if err != nil {
+		return nil, err
+	}
+
+	head := p.tlv(TAG_INIT, "01") +
+		p.tlv(TAG_INIT_METHOD, "11") +
+		p.tlv(TAG_MAI, mai) +
+		p.tlv(TAG_MCC, "0000") +
+		p.tlv(TAG_TRANSACTION_CURRENCY, "986")
+
+	merchant := p.tlv(TAG_COUNTRY_CODE, "BR") +
+		p.tlv(TAG_MERCHANT_NAME, normalizeChars(p.params.GetMerchantName())) +
+		p.tlv(TAG_MERCHANT_CITY, normalizeChars(p.params.GetMerchantCity()))
+
+	return &Template{
+		head:     sanitizePayload(head),
+		merchant: sanitizePayload(merchant),
+	}, nil
+}
+
+// Payload renders the Pix Copia e Cola payload for the given amount and TxID.
+// Surrounding whitespace is trimmed as in GenPayload; an empty amount omits tag 54 and an
+// empty TxID is rendered as "***".
+func (t *Template) Payload(amount, txid string) (string, error) {
+	buf := make([]byte, 0, t.payloadLen(amount, txid))
+	buf, err := t.AppendPayload(buf, amount, txid)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// AppendPayload appends the rendered payload to dst and returns the extended buffer,
+// allowing callers to reuse buffers and render without allocations.
+func (t *Template) AppendPayload(dst []byte, amount, txid string) ([]byte, error) {
+	amount, txid = strings.TrimSpace(amount), strings.TrimSpace(txid)
+	if amount != "" && !isValidAmount(amount) {
+		return dst, fmt.Errorf("invalid amount format: %s", amount)
+	}
+	if txid == "" {
+		txid = "***"
+	} else if !isValidTxID(txid) {
+		return dst, fmt.Errorf("txid must be alphanumeric up to 25 characters")
+	}
+
+	start := len(dst)
+	dst = append(dst, t.head...)
+	if amount != "" {
+		dst = appendTLVHeader(dst, TAG_TRANSACTION_AMOUNT, len(amount))
+		dst = append(dst, amount...)
+	}
+	dst = append(dst, t.merchant...)
+	dst = appendTLVHeader(dst, TAG_ADDITIONAL_DATA, len(TAG_TXID)+2+len(txid))
+	dst = appendTLVHeader(dst, TAG_TXID, len(txid))
+	for i := 0; i < len(txid); i++ {
+		dst = append(dst, upperASCII(txid[i]))
+	}
+	dst = appendTLVHeader(dst, TAG_CRC, 4)
+	return appendCRCHex(dst, crc16(dst[start:])), nil
+}
+
+func (t *Template) payloadLen(amount, txid string) int {
+	amount, txid = strings.TrimSpace(amount), strings.TrimSpace(txid)
+	if txid == "" {
+		txid = "***"
+	}
+	n := len(t.head) + len(t.merchant) + 16 + len(txid)
+	if amount != "" {
+		n += 4 + len(amount)
+	}
+	return n
+}
+
+func appendTLVHeader(dst []byte, tag string, length int) []byte {
+	return append(dst, tag[0], tag[1], byte('0'+length/10), byte('0'+length%10))
+}
+
+// isValidAmount mirrors amountPattern without the regexp overhead.
+func isValidAmount(s string) bool {
+	dot := len(s) - 3
+	if dot < 1 || dot > 10 || s[dot] != '.' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if i != dot && (s[i] < '0' || s[i] > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidTxID mirrors txidPattern without the regexp overhead.
+func isValidTxID(s string) bool {
+	if len(s) == 0 || len(s) > 25 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func upperASCII(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - ('a' - 'A')
+	}
+	return c
+}
@@ -0,0 +1,132 @@
+package pix
+
+import (
+	"testing"
+)
+
+func templateOptions() []Options {
+	return []Options{
+		OptPixKey("123e4567-e12b-12d1-a456-426655440000"),
+		OptMerchantName("Fulano de Tal"),
+		OptMerchantCity("ARACAJU"),
+		OptAdditionalInfo("Pedido online"),
+	}
+}
+
+func TestTemplateMatchesGenPayload(t *testing.T) {
+	tmpl, err := NewTemplate(templateOptions()...)
+	if err != nil {
+		t.Fatalf("unexpected error creating template: %v", err)
+	}
+
+	tests := []struct {
+		amount string
+		txid   string
+	}{
+		{"123.45", "W1234567890123456789"},
+		{"0.01", "abc123"},
+		{"", ""},
+		{"9999999999.99", ""},
+		// whitespace is trimmed on both paths
+		{" 10.00 ", " abc123\t"},
+		{"", "   "},
+	}
+
+	for _, tc := range tests {
+		opts := templateOptions()
+		if tc.amount != "" {
+			opts = append(opts, OptAmount(tc.amount))
+		}
+		if tc.txid != "" {
+			opts = append(opts, OptTxId(tc.txid))
+		}
+		p, err := New(opts...)
+		if err != nil {
+			t.Fatalf("unexpected error creating pix: %v", err)
+		}
+		want, err := p.GenPayload()
+		if err != nil {
+			t.Fatalf("generate payload: %v", err)
+		}
+
+		got, err := tmpl.Payload(tc.amount, tc.txid)
+		if err != nil {
+			t.Fatalf("render template: %v", err)
+		}
+		if got != want {
+			t.Fatalf("template payload mismatch:\n got %s\nwant %s", got, want)
+		}
+		if _, err := ParsePayload(got); err != nil {
+			t.Fatalf("template payload does not parse: %v", err)
+		}
+	}
+}
+
+func TestTemplateInvalidInput(t *testing.T) {
+	tmpl, err := NewTemplate(templateOptions()...)
+	if err != nil {
+		t.Fatalf("unexpected error creating template: %v", err)
+	}
+
+	if _, err := tmpl.Payload("12.345", ""); err == nil {
+		t.Fatalf("expected invalid amount error")
+	}
+	if _, err := tmpl.Payload("10.00", "INVALID TXID"); err == nil {
+		t.Fatalf("expected invalid txid error")
+	}
+
+	dynamic := []Options{
+		OptMerchantName("Fulano de Tal"),
+		OptMerchantCity("ARACAJU"),
+		OptKind(DYNAMIC),
+		OptUrl("https://example.com/invoice"),
+		OptTxId("DYNAMIC1"),
+	}
+	if _, err := NewTemplate(dynamic...); err == nil {
+		t.Fatalf("expected error for dynamic template")
+	}
+}
+
+func BenchmarkGenPayload(b *testing.B) {
+	opts := append(templateOptions(), OptAmount("123.45"), OptTxId("W1234567890123456789"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p, err := New(opts...)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := p.GenPayload(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTemplatePayload(b *testing.B) {
+	tmpl, err := NewTemplate(templateOptions()...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := tmpl.Payload("123.45", "W1234567890123456789"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTemplateAppendPayload(b *testing.B) {
+	tmpl, err := NewTemplate(templateOptions()...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	buf := make([]byte, 0, 256)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, err = tmpl.AppendPayload(buf[:0], "123.45", "W1234567890123456789")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
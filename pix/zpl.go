@@ -0,0 +1,132 @@
+package pix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+const (
+	// DefaultZPLLabelWidth and DefaultZPLLabelHeight are the label size in millimeters used
+	// when ZPLOptions leaves them zero (a common 100x50mm shelf label).
+	DefaultZPLLabelWidth  = 100.0
+	DefaultZPLLabelHeight = 50.0
+	// DefaultZPLDPI is the print head resolution used when ZPLOptions.DPI is zero.
+	DefaultZPLDPI = 203
+
+	zplMarginMM         = 2.0
+	zplMinTextMM        = 20.0
+	zplMaxMagnification = 10
+)
+
+// zplDotsPerMM maps the Zebra print head resolutions to dots per millimeter.
+var zplDotsPerMM = map[int]float64{152: 6, 203: 8, 300: 12, 600: 24}
+
+// ZPLOptions configures the Zebra ZPL II label renderer.
+type ZPLOptions struct {
+	// WidthMM and HeightMM are the label size (default DefaultZPLLabelWidth x
+	// DefaultZPLLabelHeight).
+	WidthMM  float64
+	HeightMM float64
+	// DPI is the printer resolution: 152, 203, 300 or 600 (default DefaultZPLDPI).
+	DPI int
+	// Magnification is the QR module size in dots (1-10). When zero, the largest size
+	// fitting the label height and half its width is used.
+	Magnification int
+	Level         qrcode.ErrorCorrectionLevel
+}
+
+// NewZPL builds a ZPL II label with the payload in a ^BQ QR Code field (model 2, the
+// requested error correction, automatic data mode) on the left, and the merchant, city,
+// amount and TxID as text fields on the right. Send it to the printer as is (port 9100,
+// lp or the Zebra utilities).
+func NewZPL(parsed *ParsedPayload, opts ZPLOptions) ([]byte, error) {
+	if parsed == nil || parsed.Raw == "" {
+		return nil, errors.New("pix: zpl requires a parsed payload")
+	}
+	if opts.WidthMM == 0 {
+		opts.WidthMM = DefaultZPLLabelWidth
+	}
+	if opts.HeightMM == 0 {
+		opts.HeightMM = DefaultZPLLabelHeight
+	}
+	if opts.DPI == 0 {
+		opts.DPI = DefaultZPLDPI
+	}
+	dpmm, ok := zplDotsPerMM[opts.DPI]
+	if !ok {
+		return nil, fmt.Errorf("pix: unsupported printer resolution %d dpi (expected 152, 203, 300 or 600)", opts.DPI)
+	}
+	if opts.WidthMM < 0 || opts.HeightMM < 0 {
+		return nil, errors.New("pix: label size must not be negative")
+	}
+	if opts.Magnification < 0 || opts.Magnification > zplMaxMagnification {
+		return nil, errors.New("pix: zpl magnification must be between 1 and 10")
+	}
+
+	dots := func(mm float64) int { return int(math.Round(mm * dpmm)) }
+	width, height, margin := dots(opts.WidthMM), dots(opts.HeightMM), dots(zplMarginMM)
+
+	m, err := qrcode.Encode(parsed.Raw, opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	// the quiet zone is left blank around the field, ^BQ draws only the symbol
+	dim := m.Dimension()
+	mag := opts.Magnification
+	if mag == 0 {
+		mag = (height - 2*margin) / dim
+		if byWidth := (width/2 - margin) / dim; byWidth < mag {
+			mag = byWidth
+		}
+		if mag > zplMaxMagnification {
+			mag = zplMaxMagnification
+		}
+	}
+	if mag < 1 || margin+dim*mag > height-margin {
+		return nil, fmt.Errorf("pix: the qr code does not fit a %gx%gmm label at %d dpi", opts.WidthMM, opts.HeightMM, opts.DPI)
+	}
+	textX := margin + dim*mag
+	textWidth := width - textX - margin
+	if textWidth < dots(zplMinTextMM) {
+		return nil, fmt.Errorf("pix: a %gx%gmm label leaves no room for the text fields", opts.WidthMM, opts.HeightMM)
+	}
+
+	var buf bytes.Buffer
+	// UTF-8 text, label size, then every field with hex escapes enabled (^FH)
+	fmt.Fprintf(&buf, "^XA\n^CI28\n^PW%d\n^LL%d\n", width, height)
+	quiet := m.QuietZone() * mag
+	fmt.Fprintf(&buf, "^FO%d,%d^BQN,2,%d,%s^FH\\^FD%sA,%s^FS\n",
+		margin+quiet, margin+quiet, mag, m.Level(), m.Level(), zplEscape(parsed.Raw))
+
+	y := margin + quiet
+	text := func(mm float64, lines int, s string) {
+		h := dots(mm)
+		fmt.Fprintf(&buf, "^FO%d,%d^A0N,%d,%d^FB%d,%d,0,L,0^FH\\^FD%s^FS\n",
+			textX, y, h, h, textWidth, lines, zplEscape(s))
+		y += h*lines + dots(1.5)
+	}
+	text(4.5, 2, parsed.MerchantName)
+	text(3, 1, parsed.MerchantCity)
+	if parsed.TransactionAmount != "" {
+		text(6, 1, formatBRL(parsed.TransactionAmount))
+	}
+	if txid := parsed.AdditionalDataField.TxID; txid != "" && txid != "***" {
+		text(3, 1, "TxID: "+txid)
+	}
+	if y-dots(1.5) > height-margin {
+		return nil, fmt.Errorf("pix: the text fields do not fit a %gmm tall label", opts.HeightMM)
+	}
+	buf.WriteString("^XZ\n")
+	return buf.Bytes(), nil
+}
+
+// zplEscape hex-escapes the characters ZPL reserves for commands (^ and ~) and the escape
+// character itself, for fields introduced by ^FH\.
+func zplEscape(s string) string {
+	return strings.NewReplacer(`\`, `\5C`, "^", `\5E`, "~", `\7E`).Replace(s)
+}
@@ -0,0 +1,52 @@
+package pix
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thiagozs/go-pixgen/qrcode"
+)
+
+func TestNewZPL(t *testing.T) {
+	parsed, err := ParsePayload(bacenSamplePayload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+
+	data, err := NewZPL(parsed, ZPLOptions{Level: qrcode.Quartile})
+	if err != nil {
+		t.Fatalf("render zpl: %v", err)
+	}
+	label := string(data)
+	if !strings.HasPrefix(label, "^XA\n^CI28\n^PW800\n^LL400\n") || !strings.HasSuffix(label, "^XZ\n") {
+		t.Fatalf("unexpected label frame:\n%s", label)
+	}
+	if !strings.Contains(label, "^BQN,2,") || !strings.Contains(label, ",Q^FH\\^FDQA,"+bacenSamplePayload+"^FS") {
+		t.Fatalf("expected a model 2 ECC Q qr field with the payload:\n%s", label)
+	}
+	if !strings.Contains(label, "^FDFulano de Tal^FS") || !strings.Contains(label, "^FDBRASILIA^FS") {
+		t.Fatalf("expected merchant text fields:\n%s", label)
+	}
+	if strings.Contains(label, "TxID") {
+		t.Fatalf("expected the *** txid placeholder to be left out")
+	}
+
+	parsed.MerchantName = "CAFE ^ BAR~1"
+	data, err = NewZPL(parsed, ZPLOptions{WidthMM: 101.6, HeightMM: 152.4, DPI: 300, Magnification: 4})
+	if err != nil {
+		t.Fatalf("render zpl: %v", err)
+	}
+	label = string(data)
+	if !strings.Contains(label, "^PW1219\n^LL1829\n") || !strings.Contains(label, "^BQN,2,4,M") {
+		t.Fatalf("expected a 4x6in label at 300 dpi:\n%s", label)
+	}
+	if !strings.Contains(label, `^FDCAFE \5E BAR\7E1^FS`) {
+		t.Fatalf("expected reserved characters to be escaped:\n%s", label)
+	}
+
+	for _, opts := range []ZPLOptions{{DPI: 250}, {WidthMM: 30, HeightMM: 20}, {Magnification: 11}} {
+		if _, err := NewZPL(parsed, opts); err == nil {
+			t.Fatalf("expected %+v to be rejected", opts)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package qrcode
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"image/color"
+	"sync"
+)
+
+// DefaultCacheCapacity is the number of PNG renders kept by NewCache when capacity is zero.
+const DefaultCacheCapacity = 1024
+
+// Cache is a least-recently-used cache of PNG renders keyed by the content and every render
+// option, so repeated requests for the same QR Code skip encoding and compression. It also
+// keeps the Metadata of each content and level. It is safe for concurrent use.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	hits     uint64
+	misses   uint64
+}
+
+type cacheEntry struct {
+	key  string
+	data []byte
+	meta Metadata
+}
+
+// CacheStats reports the cache usage since it was created.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Len    int
+}
+
+// NewCache returns a cache holding up to capacity renders (DefaultCacheCapacity when zero).
+func NewCache(capacity int) (*Cache, error) {
+	if capacity < 0 {
+		return nil, errors.New("qrcode: cache capacity must not be negative")
+	}
+	if capacity == 0 {
+		capacity = DefaultCacheCapacity
+	}
+	return &Cache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}, nil
+}
+
+// PNG returns New(opts), rendering it only when no equal request is cached. Every call
+// returns its own copy, so callers may modify the result without affecting later hits.
+func (c *Cache) PNG(opts QRCodeOptions) ([]byte, error) {
+	key := cacheKey(opts)
+
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		c.hits++
+		data := el.Value.(*cacheEntry).data
+		c.mu.Unlock()
+		return cloneBytes(data), nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// render outside the lock; concurrent misses for the same key render twice at worst
+	data, err := New(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		return cloneBytes(el.Value.(*cacheEntry).data), nil
+	}
+	c.store(&cacheEntry{key: key, data: cloneBytes(data)})
+	return data, nil
+}
+
+// Metadata returns Inspect(content, level), encoding content only when it is not cached.
+func (c *Cache) Metadata(content string, level ErrorCorrectionLevel) (Metadata, error) {
+	key := fmt.Sprintf("meta|%q|%d", content, level.orDefault())
+
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		c.hits++
+		meta := el.Value.(*cacheEntry).meta
+		c.mu.Unlock()
+		return meta, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	meta, err := Inspect(content, level)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.store(&cacheEntry{key: key, meta: meta})
+	}
+	return meta, nil
+}
+
+// store adds e and evicts the least recently used entries beyond capacity. c.mu must be
+// held.
+func (c *Cache) store(e *cacheEntry) {
+	c.entries[e.key] = c.order.PushFront(e)
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Len returns the number of cached renders and metadata entries.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Stats returns the hit and miss counters and the current size.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Len: c.order.Len()}
+}
+
+// cacheKey identifies a render: everything New reads from opts. Logo bytes are hashed.
+func cacheKey(opts QRCodeOptions) string {
+	content, level, size := opts.Content, opts.Level.orDefault(), opts.Size
+	if size == 0 {
+		size = 256
+	}
+	quiet := DefaultQuietZone
+	if opts.Matrix != nil {
+		content, level, quiet = opts.Matrix.Content(), opts.Matrix.Level(), opts.Matrix.QuietZone()
+	}
+	if opts.QuietZoneSet {
+		quiet = opts.QuietZone
+	}
+	logo := "-"
+	if l := opts.Logo; l != nil {
+		logo = fmt.Sprintf("%x/%g/%d/%t", sha256.Sum256(l.Data), l.Coverage, l.Padding, l.PaddingSet)
+	}
+	return fmt.Sprintf("%q|%d|%d|%s|%s|%t|%d|%s",
+		content, size, level, cacheColor(opts.Foreground), cacheColor(opts.Background),
+		opts.TransparentBackground, quiet, logo)
+}
+
+func cloneBytes(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
+
+func cacheColor(c color.Color) string {
+	if c == nil {
+		return "-"
+	}
+	r, g, b, a := c.RGBA()
+	return fmt.Sprintf("%04x%04x%04x%04x", r, g, b, a)
+}
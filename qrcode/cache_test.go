@@ -0,0 +1,108 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+func TestCachePNG(t *testing.T) {
+	c, err := NewCache(2)
+	if err != nil {
+		t.Fatalf("new cache: %v", err)
+	}
+
+	first, err := c.PNG(QRCodeOptions{Content: testContent, Size: 200})
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	want, err := New(QRCodeOptions{Content: testContent, Size: 200})
+	if err != nil {
+		t.Fatalf("reference png: %v", err)
+	}
+	if !bytes.Equal(first, want) {
+		t.Fatalf("cached render differs from New")
+	}
+
+	// callers own the returned slices, so modifying one does not corrupt later hits
+	first[0] = 0
+	again, _ := c.PNG(QRCodeOptions{Content: testContent, Size: 200})
+	if !bytes.Equal(again, want) {
+		t.Fatalf("expected the cached render to be unaffected by the caller")
+	}
+	if s := c.Stats(); s.Hits != 1 || s.Misses != 1 || s.Len != 1 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+
+	// any option change is a different render
+	if _, err := c.PNG(QRCodeOptions{Content: testContent, Size: 200, Foreground: color.NRGBA{A: 0xff, B: 0x80}}); err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	if _, err := c.PNG(QRCodeOptions{Content: testContent, Size: 300}); err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("expected the capacity to bound the cache, got %d entries", c.Len())
+	}
+	// the least recently used entry (the first render) was evicted
+	c.PNG(QRCodeOptions{Content: testContent, Size: 200})
+	if s := c.Stats(); s.Misses != 4 {
+		t.Fatalf("expected the evicted render to miss, got %+v", s)
+	}
+
+	if _, err := c.PNG(QRCodeOptions{}); err == nil {
+		t.Fatalf("expected errors to be returned and not cached")
+	}
+	if _, err := NewCache(-1); err == nil {
+		t.Fatalf("expected negative capacity to be rejected")
+	}
+}
+
+func TestCacheMetadata(t *testing.T) {
+	c, _ := NewCache(0)
+	want, err := Inspect(testContent, Quartile)
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		meta, err := c.Metadata(testContent, Quartile)
+		if err != nil || meta != want {
+			t.Fatalf("call %d: got %+v (%v), want %+v", i, meta, err, want)
+		}
+	}
+	if s := c.Stats(); s.Hits != 1 || s.Misses != 1 || s.Len != 1 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	if _, err := c.Metadata(testContent, ErrorCorrectionLevel(9)); err == nil {
+		t.Fatalf("expected an invalid level to be rejected")
+	}
+}
+
+func TestCacheKeyMatrix(t *testing.T) {
+	m, err := Encode(testContent, Quartile)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	byMatrix := cacheKey(QRCodeOptions{Matrix: m})
+	byContent := cacheKey(QRCodeOptions{Content: testContent, Level: Quartile, Size: 256, QuietZone: DefaultQuietZone, QuietZoneSet: true})
+	if byMatrix != byContent {
+		t.Fatalf("expected matrix and content requests to share a key:\n%s\n%s", byMatrix, byContent)
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := New(QRCodeOptions{Content: testContent, Size: 512}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCachePNG(b *testing.B) {
+	c, _ := NewCache(0)
+	for i := 0; i < b.N; i++ {
+		if _, err := c.PNG(QRCodeOptions{Content: testContent, Size: 512}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
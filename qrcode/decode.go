@@ -0,0 +1,93 @@
+package qrcode
+
+import (
+	"errors"
+	"image"
+	"math"
+	"sort"
+
+	"github.com/makiuchi-d/gozxing"
+	multiqr "github.com/makiuchi-d/gozxing/multi/qrcode"
+	zxingqr "github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// ErrNotFound is returned by Decode when the image holds no readable QR Code.
+var ErrNotFound = errors.New("qrcode: no qr code found in image")
+
+// Decoded is a QR Code found in an image.
+type Decoded struct {
+	Content string
+	// Bounds surrounds the finder and alignment patterns, in image coordinates.
+	Bounds image.Rectangle
+}
+
+// Decode locates and decodes every QR Code in img, in reading order (top to bottom, then
+// left to right). Codes may be rotated or skewed, as in photos and scanned documents; light
+// on dark codes are tried when no regular one is found. Transparent pixels count as white.
+// It returns ErrNotFound when nothing can be decoded.
+func Decode(img image.Image) ([]Decoded, error) {
+	if img == nil || img.Bounds().Empty() {
+		return nil, errors.New("qrcode: empty image")
+	}
+	hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_TRY_HARDER: true}
+
+	luminance := gozxing.NewLuminanceSourceFromImage(img)
+	var found []Decoded
+	for _, src := range []gozxing.LuminanceSource{luminance, luminance.Invert()} {
+		bmp, err := gozxing.NewBinaryBitmap(gozxing.NewHybridBinarizer(src))
+		if err != nil {
+			return nil, err
+		}
+		// the multi reader finds several codes; the single reader copes better with noisy
+		// scans where only one set of finder patterns is reliable
+		results, _ := multiqr.NewQRCodeMultiReader().DecodeMultiple(bmp, hints)
+		if len(results) == 0 {
+			if r, err := zxingqr.NewQRCodeReader().Decode(bmp, hints); err == nil {
+				results = append(results, r)
+			}
+		}
+		for _, r := range results {
+			found = appendDecoded(found, Decoded{Content: r.GetText(), Bounds: resultBounds(r, img.Bounds().Min)})
+		}
+		if len(found) > 0 {
+			break
+		}
+	}
+	if len(found) == 0 {
+		return nil, ErrNotFound
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i].Bounds, found[j].Bounds
+		// codes whose rows overlap are on the same line
+		if a.Max.Y > b.Min.Y && b.Max.Y > a.Min.Y {
+			return a.Min.X < b.Min.X
+		}
+		return a.Min.Y < b.Min.Y
+	})
+	return found, nil
+}
+
+// appendDecoded skips codes already found at the same place.
+func appendDecoded(found []Decoded, d Decoded) []Decoded {
+	for _, f := range found {
+		if f.Content == d.Content && f.Bounds.Overlaps(d.Bounds) {
+			return found
+		}
+	}
+	return append(found, d)
+}
+
+func resultBounds(r *gozxing.Result, origin image.Point) image.Rectangle {
+	points := r.GetResultPoints()
+	if len(points) == 0 {
+		return image.Rectangle{}
+	}
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		minX, maxX = math.Min(minX, p.GetX()), math.Max(maxX, p.GetX())
+		minY, maxY = math.Min(minY, p.GetY()), math.Max(maxY, p.GetY())
+	}
+	return image.Rect(int(minX), int(minY), int(math.Ceil(maxX))+1, int(math.Ceil(maxY))+1).Add(origin)
+}
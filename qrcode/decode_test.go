@@ -0,0 +1,111 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"testing"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+)
+
+func renderTestImage(t *testing.T, opts QRCodeOptions) image.Image {
+	t.Helper()
+	data, err := New(opts)
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	return decodeTestPNG(t, data)
+}
+
+// rotateTestImage rotates img by deg degrees around its center on a larger white canvas.
+func rotateTestImage(img image.Image, deg float64) image.Image {
+	side := float64(img.Bounds().Dx())
+	canvas := int(side * 1.5)
+	dst := image.NewRGBA(image.Rect(0, 0, canvas, canvas))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+
+	sin, cos := math.Sincos(deg * math.Pi / 180)
+	c, h := float64(canvas)/2, side/2
+	// maps source to destination: translate to the origin, rotate, move to the canvas center
+	m := f64.Aff3{
+		cos, -sin, c - cos*h + sin*h,
+		sin, cos, c - sin*h - cos*h,
+	}
+	draw.BiLinear.Transform(dst, m, img, img.Bounds(), draw.Over, nil)
+	return dst
+}
+
+func TestDecode(t *testing.T) {
+	img := renderTestImage(t, QRCodeOptions{Content: testContent, Size: 300})
+
+	cases := map[string]image.Image{
+		"upright":   img,
+		"rotated90": rotateTestImage(img, 90),
+		"rotated30": rotateTestImage(img, 30),
+	}
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, img, &jpeg.Options{Quality: 60}); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	jpgImg, err := jpeg.Decode(&jpg)
+	if err != nil {
+		t.Fatalf("decode jpeg: %v", err)
+	}
+	cases["jpeg"] = jpgImg
+	inverted := image.NewGray(img.Bounds())
+	for y := 0; y < inverted.Bounds().Dy(); y++ {
+		for x := 0; x < inverted.Bounds().Dx(); x++ {
+			g := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+			inverted.SetGray(x, y, color.Gray{Y: 255 - g.Y})
+		}
+	}
+	cases["inverted"] = inverted
+
+	for name, img := range cases {
+		got, err := Decode(img)
+		if err != nil {
+			t.Fatalf("%s: decode: %v", name, err)
+		}
+		if len(got) != 1 || got[0].Content != testContent {
+			t.Fatalf("%s: unexpected result %+v", name, got)
+		}
+	}
+
+	blank := image.NewGray(image.Rect(0, 0, 100, 100))
+	if _, err := Decode(blank); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDecodeMultiple(t *testing.T) {
+	contents := []string{"first code", "second code", "third code"}
+	sheet := image.NewRGBA(image.Rect(0, 0, 700, 600))
+	draw.Draw(sheet, sheet.Bounds(), image.White, image.Point{}, draw.Src)
+	// two codes on the top line, right one first, and one below
+	at := []image.Point{{400, 20}, {40, 60}, {200, 330}}
+	for i, content := range []string{contents[1], contents[0], contents[2]} {
+		img := renderTestImage(t, QRCodeOptions{Content: content, Size: 240})
+		draw.Draw(sheet, img.Bounds().Add(at[i]), img, image.Point{}, draw.Src)
+	}
+
+	got, err := Decode(sheet)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got) != len(contents) {
+		t.Fatalf("expected %d codes, got %+v", len(contents), got)
+	}
+	for i, d := range got {
+		if d.Content != contents[i] {
+			t.Fatalf("code %d: expected %q in reading order, got %q", i, contents[i], d.Content)
+		}
+		if d.Bounds.Empty() {
+			t.Fatalf("code %d: missing bounds", i)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Half-block characters: each one draws the upper and lower halves of a terminal cell, so a
+// line of text carries two rows of modules.
+const (
+	blockFull  = "█"
+	blockUpper = "▀"
+	blockLower = "▄"
+	blockEmpty = " "
+)
+
+// HalfBlockOptions configures the compact Unicode terminal renderer.
+type HalfBlockOptions struct {
+	Content string
+	// Matrix, when set, is rendered instead of encoding Content; Level is then ignored.
+	Matrix *Matrix
+	Level  ErrorCorrectionLevel
+	// QuietZone is the border width in modules; used only when QuietZoneSet is true,
+	// otherwise the matrix quiet zone (DefaultQuietZone) applies.
+	QuietZone    int
+	QuietZoneSet bool
+	// Inverted draws dark modules as blocks, for terminals with a light background. By
+	// default light modules are drawn, which suits dark terminals.
+	Inverted bool
+	// Scale repeats each module Scale times in both directions. When zero, the largest
+	// scale fitting TerminalWidth columns is used (1 when TerminalWidth is zero too).
+	Scale         int
+	TerminalWidth int
+}
+
+// NewHalfBlock renders the QR Code with Unicode half blocks (▀▄█), printing two rows of
+// modules per line so the code keeps its aspect ratio at half the height of NewASCII.
+func NewHalfBlock(opts HalfBlockOptions) (string, error) {
+	m, err := matrixFor(opts.Matrix, opts.Content, opts.Level, opts.QuietZone, opts.QuietZoneSet, nil)
+	if err != nil {
+		return "", err
+	}
+
+	dim := m.Dimension()
+	scale := opts.Scale
+	if scale < 0 {
+		return "", errors.New("qrcode: scale must not be negative")
+	}
+	if scale == 0 {
+		scale = 1
+		if opts.TerminalWidth > 0 {
+			scale = opts.TerminalWidth / dim
+		}
+	}
+	if scale < 1 {
+		scale = 1
+	}
+	if opts.TerminalWidth > 0 && dim*scale > opts.TerminalWidth {
+		return "", fmt.Errorf("qrcode: terminal too narrow: %d columns needed, %d available", dim*scale, opts.TerminalWidth)
+	}
+
+	drawn := func(x, y int) bool {
+		if y >= dim*scale {
+			// padding row below an odd height belongs to the quiet zone
+			return !opts.Inverted
+		}
+		return m.Get(x/scale, y/scale) == opts.Inverted
+	}
+
+	var buf strings.Builder
+	for y := 0; y < dim*scale; y += 2 {
+		if y > 0 {
+			buf.WriteByte('\n')
+		}
+		for x := 0; x < dim*scale; x++ {
+			switch top, bottom := drawn(x, y), drawn(x, y+1); {
+			case top && bottom:
+				buf.WriteString(blockFull)
+			case top:
+				buf.WriteString(blockUpper)
+			case bottom:
+				buf.WriteString(blockLower)
+			default:
+				buf.WriteString(blockEmpty)
+			}
+		}
+	}
+	return buf.String(), nil
+}
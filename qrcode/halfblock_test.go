@@ -0,0 +1,69 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// decodeHalfBlocks expands half-block output back into rows of drawn cells.
+func decodeHalfBlocks(s string) [][]bool {
+	var rows [][]bool
+	for _, line := range strings.Split(s, "\n") {
+		top := make([]bool, 0, utf8.RuneCountInString(line))
+		bottom := make([]bool, 0, cap(top))
+		for _, r := range line {
+			top = append(top, r == '█' || r == '▀')
+			bottom = append(bottom, r == '█' || r == '▄')
+		}
+		rows = append(rows, top, bottom)
+	}
+	return rows
+}
+
+func TestNewHalfBlock(t *testing.T) {
+	m, err := Encode(testContent, Medium)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	for _, inverted := range []bool{false, true} {
+		out, err := NewHalfBlock(HalfBlockOptions{Matrix: m, Inverted: inverted})
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		lines := strings.Split(out, "\n")
+		if len(lines) != (m.Dimension()+1)/2 {
+			t.Fatalf("expected %d lines, got %d", (m.Dimension()+1)/2, len(lines))
+		}
+		rows := decodeHalfBlocks(out)
+		for y := 0; y < m.Dimension(); y++ {
+			for x := 0; x < m.Dimension(); x++ {
+				if rows[y][x] != (m.Get(x, y) == inverted) {
+					t.Fatalf("inverted=%v: module %d,%d differs", inverted, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestNewHalfBlockAutoScale(t *testing.T) {
+	m, err := Encode(testContent, Medium)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	width := m.Dimension()*2 + 5
+
+	out, err := NewHalfBlock(HalfBlockOptions{Matrix: m, TerminalWidth: width})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	first := strings.SplitN(out, "\n", 2)[0]
+	if got := utf8.RuneCountInString(first); got != m.Dimension()*2 {
+		t.Fatalf("expected scale 2 (%d columns), got %d", m.Dimension()*2, got)
+	}
+
+	if _, err := NewHalfBlock(HalfBlockOptions{Matrix: m, TerminalWidth: m.Dimension() - 1}); err == nil {
+		t.Fatalf("expected narrow terminal to be rejected")
+	}
+}
@@ -0,0 +1,167 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// ErrorCorrectionLevel selects how much of the symbol can be damaged and still be read.
+// The zero value selects Medium, the level used by every renderer by default.
+type ErrorCorrectionLevel int
+
+const (
+	// Low recovers ~7% of the modules and keeps them as large as possible.
+	Low ErrorCorrectionLevel = iota + 1
+	// Medium recovers ~15% of the modules.
+	Medium
+	// Quartile recovers ~25% of the modules.
+	Quartile
+	// High recovers ~30% of the modules; recommended for posters and logo overlays.
+	High
+)
+
+func (l ErrorCorrectionLevel) String() string {
+	switch l.orDefault() {
+	case Low:
+		return "L"
+	case Medium:
+		return "M"
+	case Quartile:
+		return "Q"
+	case High:
+		return "H"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// MarshalText encodes the level as its single-letter name.
+func (l ErrorCorrectionLevel) MarshalText() ([]byte, error) {
+	if err := l.validate(); err != nil {
+		return nil, err
+	}
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText decodes a level from its name (see ParseErrorCorrectionLevel).
+func (l *ErrorCorrectionLevel) UnmarshalText(text []byte) error {
+	level, err := ParseErrorCorrectionLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// ParseErrorCorrectionLevel parses L, M, Q or H (or low, medium, quartile, high).
+// An empty string returns the default level.
+func ParseErrorCorrectionLevel(s string) (ErrorCorrectionLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return Medium, nil
+	case "l", "low":
+		return Low, nil
+	case "m", "medium":
+		return Medium, nil
+	case "q", "quartile":
+		return Quartile, nil
+	case "h", "high":
+		return High, nil
+	default:
+		return 0, fmt.Errorf("qrcode: invalid error correction level %q (expected L, M, Q or H)", s)
+	}
+}
+
+func (l ErrorCorrectionLevel) orDefault() ErrorCorrectionLevel {
+	if l == 0 {
+		return Medium
+	}
+	return l
+}
+
+func (l ErrorCorrectionLevel) validate() error {
+	if l < 0 || l > High {
+		return errors.New("qrcode: invalid error correction level")
+	}
+	return nil
+}
+
+func (l ErrorCorrectionLevel) recoveryLevel() qrcode.RecoveryLevel {
+	switch l.orDefault() {
+	case Low:
+		return qrcode.Low
+	case Quartile:
+		return qrcode.High
+	case High:
+		return qrcode.Highest
+	default:
+		return qrcode.Medium
+	}
+}
+
+// Metadata describes the symbol chosen to encode a content.
+type Metadata struct {
+	// Version is the QR version (1-40).
+	Version int `json:"version"`
+	// Modules is the number of modules per side, excluding the quiet zone.
+	Modules int `json:"modules"`
+	// Level is the error correction level.
+	Level ErrorCorrectionLevel `json:"level"`
+	// Length is the content length in bytes.
+	Length int `json:"length"`
+	// Headroom is how many bytes can still be appended to the content before the
+	// symbol needs a larger version.
+	Headroom int `json:"headroom"`
+	// Capacity is Length plus Headroom.
+	Capacity int `json:"capacity"`
+}
+
+// Inspect reports the QR version and capacity used to encode content at level. Use
+// Matrix.Metadata when the symbol is already encoded.
+func Inspect(content string, level ErrorCorrectionLevel) (Metadata, error) {
+	m, err := Encode(content, level)
+	if err != nil {
+		return Metadata{}, err
+	}
+	return m.Metadata(), nil
+}
+
+// Metadata reports the version and capacity of the symbol without encoding it again.
+func (m *Matrix) Metadata() Metadata {
+	headroom := measureHeadroom(m.content, m.version, m.level.recoveryLevel())
+	return Metadata{
+		Version:  m.version,
+		Modules:  m.Size(),
+		Level:    m.level,
+		Length:   len(m.content),
+		Headroom: headroom,
+		Capacity: len(m.content) + headroom,
+	}
+}
+
+// maxByteCapacity is the byte-mode capacity of a version 40 symbol at level L.
+const maxByteCapacity = 2953
+
+// measureHeadroom binary searches how many bytes can be appended to content while the
+// encoder keeps the same version. Encoding only the data segments is cheap, so probing
+// with the real encoder is both exact and fast enough.
+func measureHeadroom(content string, version int, level qrcode.RecoveryLevel) int {
+	fits := func(extra int) bool {
+		code, err := qrcode.New(content+strings.Repeat("x", extra), level)
+		return err == nil && code.VersionNumber == version
+	}
+
+	lo, hi := 0, maxByteCapacity
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if fits(mid) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo
+}
@@ -0,0 +1,88 @@
+package qrcode
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseErrorCorrectionLevel(t *testing.T) {
+	tests := map[string]ErrorCorrectionLevel{
+		"":       Medium,
+		"l":      Low,
+		"M":      Medium,
+		"q":      Quartile,
+		"High":   High,
+		"medium": Medium,
+	}
+	for in, want := range tests {
+		got, err := ParseErrorCorrectionLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseErrorCorrectionLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseErrorCorrectionLevel("X"); err == nil {
+		t.Fatalf("expected error for invalid level")
+	}
+}
+
+func TestInspect(t *testing.T) {
+	low, err := Inspect(testContent, Low)
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	high, err := Inspect(testContent, High)
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+
+	if low.Version >= high.Version {
+		t.Fatalf("expected higher version for level H, got L=%d H=%d", low.Version, high.Version)
+	}
+	if high.Modules != 17+4*high.Version {
+		t.Fatalf("unexpected module count %d for version %d", high.Modules, high.Version)
+	}
+	if low.Length != len(testContent) || low.Capacity != low.Length+low.Headroom {
+		t.Fatalf("unexpected capacity data: %+v", low)
+	}
+	if m, err := Encode(testContent, High); err != nil || m.Metadata() != high {
+		t.Fatalf("expected Matrix.Metadata to match Inspect, got %v (%v)", high, err)
+	}
+	for _, tc := range []struct {
+		extra   int
+		version int
+		same    bool
+	}{
+		{low.Headroom, low.Version, true},
+		{low.Headroom + 1, low.Version, false},
+	} {
+		meta, err := Inspect(testContent+strings.Repeat("x", tc.extra), Low)
+		if err != nil {
+			t.Fatalf("inspect: %v", err)
+		}
+		if (meta.Version == tc.version) != tc.same {
+			t.Fatalf("appending %d bytes: version %d, expected same=%v as %d", tc.extra, meta.Version, tc.same, tc.version)
+		}
+	}
+
+	m, err := Encode(testContent, High)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if m.Size() != high.Modules {
+		t.Fatalf("matrix has %d modules, metadata reports %d", m.Size(), high.Modules)
+	}
+
+	data, err := json.Marshal(high)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded Metadata
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != high {
+		t.Fatalf("metadata json round trip failed: %s (%v)", data, err)
+	}
+
+	if _, err := Inspect(testContent, ErrorCorrectionLevel(9)); err == nil {
+		t.Fatalf("expected error for invalid level")
+	}
+}
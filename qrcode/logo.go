@@ -0,0 +1,200 @@
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // logos may be provided as JPEG
+	"math"
+)
+
+const (
+	// DefaultLogoCoverage is the fraction of the symbol area cleared for a logo by default.
+	DefaultLogoCoverage = 0.12
+	// MaxLogoCoverage is the largest fraction of the symbol area a logo may cover. Logos
+	// always use level High, which recovers ~30% of the codewords; keeping the cleared area
+	// well below that leaves room for print defects.
+	MaxLogoCoverage = 0.2
+)
+
+// Logo is an image placed at the center of the QR Code.
+type Logo struct {
+	// Data holds a PNG, JPEG or SVG document. SVG logos are supported only by NewSVG.
+	Data []byte
+	// Coverage is the fraction of the symbol area (quiet zone excluded) cleared for the
+	// logo, padding included. Zero selects DefaultLogoCoverage; values above
+	// MaxLogoCoverage are rejected.
+	Coverage float64
+	// Padding is the number of light modules kept around the logo inside the cleared
+	// area (default 1).
+	Padding    int
+	PaddingSet bool
+}
+
+// logoArea is the square of modules cleared for the logo, in symbol coordinates.
+type logoArea struct {
+	start, size, padding int
+}
+
+func (l *Logo) validate() error {
+	if len(l.Data) == 0 {
+		return errors.New("qrcode: logo data must not be empty")
+	}
+	if l.Coverage < 0 || l.Coverage > MaxLogoCoverage {
+		return fmt.Errorf("qrcode: logo coverage must be between 0 and %.2f", MaxLogoCoverage)
+	}
+	if l.Padding < 0 {
+		return errors.New("qrcode: logo padding must not be negative")
+	}
+	return nil
+}
+
+func (l *Logo) isSVG() bool {
+	head := l.Data
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	return bytes.Contains(head, []byte("<svg"))
+}
+
+// area computes the centered square of modules reserved for the logo. The side keeps the
+// symbol's parity so the square is exactly centered.
+func (l *Logo) area(modules int) (logoArea, error) {
+	coverage := l.Coverage
+	if coverage == 0 {
+		coverage = DefaultLogoCoverage
+	}
+	padding := 1
+	if l.PaddingSet {
+		padding = l.Padding
+	}
+
+	side := int(math.Sqrt(coverage * float64(modules*modules)))
+	if (modules-side)%2 != 0 {
+		side--
+	}
+	if side-2*padding < 1 {
+		return logoArea{}, errors.New("qrcode: symbol too small for a logo")
+	}
+	return logoArea{start: (modules - side) / 2, size: side, padding: padding}, nil
+}
+
+// clear returns a copy of m without the dark modules under the logo area, so the area
+// renders as background.
+func (a logoArea) clear(m *Matrix) *Matrix {
+	out := make([][]bool, len(m.modules))
+	for y, row := range m.modules {
+		out[y] = append([]bool(nil), row...)
+		if y < a.start || y >= a.start+a.size {
+			continue
+		}
+		for x := a.start; x < a.start+a.size; x++ {
+			out[y][x] = false
+		}
+	}
+	c := *m
+	c.modules = out
+	return &c
+}
+
+// prepareLogo validates the logo and returns the reserved area and the matrix with the
+// area cleared.
+func prepareLogo(logo *Logo, m *Matrix) (logoArea, *Matrix, error) {
+	if err := logo.validate(); err != nil {
+		return logoArea{}, nil, err
+	}
+	area, err := logo.area(m.Size())
+	if err != nil {
+		return logoArea{}, nil, err
+	}
+	return area, area.clear(m), nil
+}
+
+// drawLogo composes a raster logo over the rendered QR image. The logo keeps its aspect
+// ratio and is centered inside the padded area.
+func drawLogo(img *image.Paletted, logo *Logo, area logoArea, m *Matrix) (image.Image, error) {
+	if logo.isSVG() {
+		return nil, errors.New("qrcode: svg logos are supported only in svg output")
+	}
+	src, _, err := image.Decode(bytes.NewReader(logo.Data))
+	if err != nil {
+		return nil, fmt.Errorf("qrcode: decode logo: %w", err)
+	}
+
+	// renderImage draws every module as a square of scale pixels
+	quiet, scale := m.QuietZone(), img.Bounds().Dx()/m.Dimension()
+	pixel := func(m int) int { return m * scale }
+	x0 := pixel(quiet + area.start + area.padding)
+	x1 := pixel(quiet + area.start + area.size - area.padding)
+	box := fitRect(src.Bounds(), image.Rect(x0, x0, x1, x1))
+
+	out := image.NewNRGBA(img.Bounds())
+	draw.Draw(out, out.Bounds(), img, image.Point{}, draw.Src)
+	draw.Draw(out, box, scaleImage(src, box.Dx(), box.Dy()), image.Point{}, draw.Over)
+	return out, nil
+}
+
+// fitRect centers a rectangle with the aspect ratio of src inside dst.
+func fitRect(src, dst image.Rectangle) image.Rectangle {
+	sw, sh := float64(src.Dx()), float64(src.Dy())
+	scale := math.Min(float64(dst.Dx())/sw, float64(dst.Dy())/sh)
+	w, h := int(sw*scale), int(sh*scale)
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	x := dst.Min.X + (dst.Dx()-w)/2
+	y := dst.Min.Y + (dst.Dy()-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// scaleImage resizes src to w x h averaging the source pixels covered by each target
+// pixel, which keeps downscaled logos smooth without extra dependencies.
+func scaleImage(src image.Image, w, h int) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy0 := b.Min.Y + y*b.Dy()/h
+		sy1 := b.Min.Y + (y+1)*b.Dy()/h
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for x := 0; x < w; x++ {
+			sx0 := b.Min.X + x*b.Dx()/w
+			sx1 := b.Min.X + (x+1)*b.Dx()/w
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			// average in premultiplied space, then store as non-premultiplied
+			c := color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n)}
+			dst.Set(x, y, c)
+		}
+	}
+	return dst
+}
+
+// logoDataURI returns the logo as a data URI for embedding in SVG documents.
+func logoDataURI(logo *Logo) (string, error) {
+	if logo.isSVG() {
+		return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(logo.Data), nil
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(logo.Data))
+	if err != nil || cfg.Width == 0 || cfg.Height == 0 {
+		return "", errors.New("qrcode: logo must be a PNG, JPEG or SVG image")
+	}
+	return "data:image/" + format + ";base64," + base64.StdEncoding.EncodeToString(logo.Data), nil
+}
@@ -0,0 +1,96 @@
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func testLogoPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			img.SetNRGBA(x, y, red)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode logo: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestLogoArea(t *testing.T) {
+	m, err := Encode(testContent, High)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	n := m.Size()
+
+	area, cleared, err := prepareLogo(&Logo{Data: []byte{1}, Coverage: MaxLogoCoverage}, m)
+	if err != nil {
+		t.Fatalf("prepare logo: %v", err)
+	}
+	if area.start*2+area.size != n {
+		t.Fatalf("area %+v not centered in %d modules", area, n)
+	}
+	if got := float64(area.size*area.size) / float64(n*n); got > MaxLogoCoverage {
+		t.Fatalf("coverage %.3f exceeds limit", got)
+	}
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			inside := x >= area.start && x < area.start+area.size && y >= area.start && y < area.start+area.size
+			if inside && cleared.modules[y][x] {
+				t.Fatalf("module %d,%d not cleared", x, y)
+			}
+			if !inside && cleared.modules[y][x] != m.modules[y][x] {
+				t.Fatalf("module %d,%d outside the logo changed", x, y)
+			}
+		}
+	}
+
+	if _, _, err := prepareLogo(&Logo{Data: []byte{1}, Coverage: 0.3}, m); err == nil {
+		t.Fatalf("expected coverage above the limit to be rejected")
+	}
+}
+
+func TestNewWithLogo(t *testing.T) {
+	data, err := New(QRCodeOptions{Content: testContent, Size: 300, Logo: &Logo{Data: testLogoPNG(t)}})
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	img := decodeTestPNG(t, data)
+	center := img.Bounds().Dx() / 2
+	c := color.NRGBAModel.Convert(img.At(center, center)).(color.NRGBA)
+	if c != (color.NRGBA{R: 0xff, A: 0xff}) {
+		t.Fatalf("expected logo at the center, got %+v", c)
+	}
+
+	svgLogo := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1"><rect width="1" height="1"/></svg>`)
+	if _, err := New(QRCodeOptions{Content: testContent, Logo: &Logo{Data: svgLogo}}); err == nil {
+		t.Fatalf("expected svg logo to be rejected for png output")
+	}
+}
+
+func TestNewSVGWithLogo(t *testing.T) {
+	data, err := NewSVG(SVGOptions{Content: testContent, Logo: &Logo{Data: testLogoPNG(t)}})
+	if err != nil {
+		t.Fatalf("render svg: %v", err)
+	}
+	if !strings.Contains(string(data), `xlink:href="data:image/png;base64,`) {
+		t.Fatalf("expected embedded png logo, got %s", data)
+	}
+
+	withLevel, err := NewSVG(SVGOptions{Content: testContent, Level: Low, Logo: &Logo{Data: testLogoPNG(t)}})
+	if err != nil {
+		t.Fatalf("render svg: %v", err)
+	}
+	if !bytes.Equal(data, withLevel) {
+		t.Fatalf("expected logo to force level H regardless of the requested level")
+	}
+}
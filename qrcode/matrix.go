@@ -0,0 +1,103 @@
+package qrcode
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// Matrix is the module grid of an encoded QR Code plus the quiet zone around it. Every
+// renderer in this package draws from a Matrix, so the PNG, SVG, PDF and terminal outputs
+// of the same content are module-for-module identical, and custom renderers only need Get.
+//
+// A Matrix is immutable; WithQuietZone returns a copy sharing the modules.
+type Matrix struct {
+	content string
+	modules [][]bool
+	quiet   int
+	version int
+	level   ErrorCorrectionLevel
+}
+
+// Encode encodes content at level (the zero value selects Medium) into a Matrix with the
+// DefaultQuietZone.
+func Encode(content string, level ErrorCorrectionLevel) (*Matrix, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, errors.New("qrcode: content must not be empty")
+	}
+	if err := level.validate(); err != nil {
+		return nil, err
+	}
+	code, err := qrcode.New(content, level.recoveryLevel())
+	if err != nil {
+		return nil, err
+	}
+	code.DisableBorder = true
+	return &Matrix{
+		content: content,
+		modules: code.Bitmap(),
+		quiet:   DefaultQuietZone,
+		version: code.VersionNumber,
+		level:   level.orDefault(),
+	}, nil
+}
+
+// Content returns the encoded text.
+func (m *Matrix) Content() string { return m.content }
+
+// Size returns the number of modules per side of the symbol, excluding the quiet zone.
+func (m *Matrix) Size() int { return len(m.modules) }
+
+// QuietZone returns the width of the light border around the symbol, in modules.
+func (m *Matrix) QuietZone() int { return m.quiet }
+
+// Dimension returns the number of modules per side, quiet zone included.
+func (m *Matrix) Dimension() int { return len(m.modules) + 2*m.quiet }
+
+// Version returns the QR Code version (1-40).
+func (m *Matrix) Version() int { return m.version }
+
+// Level returns the error correction level the symbol was encoded with.
+func (m *Matrix) Level() ErrorCorrectionLevel { return m.level }
+
+// Get reports whether the module at column x, row y is dark. Coordinates include the quiet
+// zone: (0, 0) is the top-left corner of the border and both range up to Dimension()-1.
+// Quiet zone and out-of-range modules are light.
+func (m *Matrix) Get(x, y int) bool {
+	x, y = x-m.quiet, y-m.quiet
+	if y < 0 || y >= len(m.modules) || x < 0 || x >= len(m.modules) {
+		return false
+	}
+	return m.modules[y][x]
+}
+
+// WithQuietZone returns a copy of the matrix with a quiet zone of n modules.
+func (m *Matrix) WithQuietZone(n int) (*Matrix, error) {
+	if n < 0 {
+		return nil, errors.New("qrcode: quiet zone must not be negative")
+	}
+	c := *m
+	c.quiet = n
+	return &c, nil
+}
+
+// matrixFor returns the matrix a renderer draws: the caller's matrix, or content encoded at
+// level, with the quiet zone override applied. Logos require level High.
+func matrixFor(m *Matrix, content string, level ErrorCorrectionLevel, quiet int, quietSet bool, logo *Logo) (*Matrix, error) {
+	if m == nil {
+		if logo != nil {
+			level = High
+		}
+		var err error
+		if m, err = Encode(content, level); err != nil {
+			return nil, err
+		}
+	} else if logo != nil && m.level != High {
+		return nil, errors.New("qrcode: logo requires a matrix encoded at level H")
+	}
+	if quietSet {
+		return m.WithQuietZone(quiet)
+	}
+	return m, nil
+}
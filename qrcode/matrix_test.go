@@ -0,0 +1,89 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodeMatrix(t *testing.T) {
+	m, err := Encode(testContent, Medium)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if m.Size() != 17+4*m.Version() {
+		t.Fatalf("size %d does not match version %d", m.Size(), m.Version())
+	}
+	if m.QuietZone() != DefaultQuietZone || m.Dimension() != m.Size()+2*DefaultQuietZone {
+		t.Fatalf("unexpected quiet zone %d / dimension %d", m.QuietZone(), m.Dimension())
+	}
+	if m.Content() != testContent || m.Level() != Medium {
+		t.Fatalf("unexpected content or level")
+	}
+
+	q := m.QuietZone()
+	// top-left finder pattern: dark outer ring, light separator
+	if !m.Get(q, q) || !m.Get(q+6, q) || m.Get(q+7, q) {
+		t.Fatalf("finder pattern not where expected")
+	}
+	for _, p := range [][2]int{{0, 0}, {q - 1, q}, {-1, 5}, {m.Dimension(), 5}} {
+		if m.Get(p[0], p[1]) {
+			t.Fatalf("quiet zone or outside module %v should be light", p)
+		}
+	}
+
+	bare, err := m.WithQuietZone(0)
+	if err != nil {
+		t.Fatalf("with quiet zone: %v", err)
+	}
+	if !bare.Get(0, 0) || m.QuietZone() != DefaultQuietZone {
+		t.Fatalf("WithQuietZone must shift coordinates without changing the original")
+	}
+	if _, err := m.WithQuietZone(-1); err == nil {
+		t.Fatalf("expected negative quiet zone to be rejected")
+	}
+	if _, err := Encode(" ", Medium); err == nil {
+		t.Fatalf("expected empty content to be rejected")
+	}
+}
+
+func TestRenderersShareMatrix(t *testing.T) {
+	m, err := Encode(testContent, Quartile)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	ascii, err := NewASCII(ASCIIOptions{Matrix: m, BlackChar: "#", WhiteChar: "."})
+	if err != nil {
+		t.Fatalf("render ascii: %v", err)
+	}
+	framed, _ := m.WithQuietZone(1)
+	rows := strings.Split(ascii, "\n")
+	if len(rows) != framed.Dimension() {
+		t.Fatalf("expected %d rows, got %d", framed.Dimension(), len(rows))
+	}
+	for y, row := range rows {
+		for x, c := range row {
+			if (c == '#') != framed.Get(x, y) {
+				t.Fatalf("ascii module %d,%d differs from matrix", x, y)
+			}
+		}
+	}
+
+	data, err := New(QRCodeOptions{Matrix: m, Size: m.Dimension() * 4})
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	img := decodeTestPNG(t, data)
+	for y := 0; y < m.Dimension(); y++ {
+		for x := 0; x < m.Dimension(); x++ {
+			r, _, _, _ := img.At(x*4+2, y*4+2).RGBA()
+			if (r == 0) != m.Get(x, y) {
+				t.Fatalf("png module %d,%d differs from matrix", x, y)
+			}
+		}
+	}
+
+	if _, err := New(QRCodeOptions{Matrix: m, Logo: &Logo{Data: []byte{1}}}); err == nil {
+		t.Fatalf("expected logo over a level Q matrix to be rejected")
+	}
+}
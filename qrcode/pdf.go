@@ -0,0 +1,169 @@
+package qrcode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	pointsPerMM     = 72 / 25.4
+	cropMarkGapMM   = 3.0
+	cropMarkLenMM   = 5.0
+	cropMarkWidthPt = 0.25
+)
+
+// PDFOptions configures the print-ready PDF renderer.
+type PDFOptions struct {
+	Content string
+	// Matrix, when set, is rendered instead of encoding Content; Level is then ignored.
+	Matrix *Matrix
+	// SizeMM is the physical side of the QR Code, quiet zone included (default 50mm).
+	SizeMM float64
+	// BleedMM extends the white background beyond the trim box for full-bleed printing.
+	BleedMM float64
+	// CropMarks draws corner marks outside the bleed area to guide trimming.
+	CropMarks bool
+	// QuietZone is the border width in modules; used only when QuietZoneSet is true,
+	// otherwise DefaultQuietZone applies.
+	QuietZone    int
+	QuietZoneSet bool
+	Level        ErrorCorrectionLevel
+}
+
+// NewPDF renders the QR Code as a single-page PDF where modules are vector rectangles
+// placed at the requested physical size. The page declares TrimBox and BleedBox so print
+// workflows can impose it directly.
+func NewPDF(opts PDFOptions) ([]byte, error) {
+	if opts.SizeMM < 0 || opts.BleedMM < 0 {
+		return nil, errors.New("qrcode: pdf size and bleed must not be negative")
+	}
+
+	m, err := matrixFor(opts.Matrix, opts.Content, opts.Level, opts.QuietZone, opts.QuietZoneSet, nil)
+	if err != nil {
+		return nil, err
+	}
+	quiet := m.QuietZone()
+
+	size := opts.SizeMM
+	if size == 0 {
+		size = 50
+	}
+	bleed := opts.BleedMM
+	margin := bleed
+	if opts.CropMarks {
+		margin = cropMarkGap(bleed) + cropMarkLenMM
+	}
+	page := size + 2*margin
+	module := size / float64(m.Dimension())
+
+	var content bytes.Buffer
+	// white background over the bleed box
+	fmt.Fprintf(&content, "1 g\n%s %s %s %s re f\n",
+		pdfNum(margin-bleed), pdfNum(margin-bleed), pdfNum(size+2*bleed), pdfNum(size+2*bleed))
+	content.WriteString("0 g\n")
+	for _, r := range mergeDarkModules(m.modules) {
+		x := margin + float64(r.x+quiet)*module
+		y := margin + size - float64(r.y+quiet+r.h)*module
+		fmt.Fprintf(&content, "%s %s %s %s re\n",
+			pdfNum(x), pdfNum(y), pdfNum(float64(r.w)*module), pdfNum(float64(r.h)*module))
+	}
+	content.WriteString("f\n")
+	if opts.CropMarks {
+		writeCropMarks(&content, margin, size, bleed)
+	}
+
+	// content stream coordinates are in mm; scale them to points once
+	stream := fmt.Sprintf("q %s 0 0 %s 0 0 cm\n%sQ\n", pdfNum(pointsPerMM), pdfNum(pointsPerMM), content.String())
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write([]byte(stream)); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	box := func(x0, y0, x1, y1 float64) string {
+		return fmt.Sprintf("[%s %s %s %s]", pdfNum(x0*pointsPerMM), pdfNum(y0*pointsPerMM),
+			pdfNum(x1*pointsPerMM), pdfNum(y1*pointsPerMM))
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox %s /BleedBox %s /TrimBox %s /Resources << >> /Contents 4 0 R >>",
+			box(0, 0, page, page),
+			box(margin-bleed, margin-bleed, margin+size+bleed, margin+size+bleed),
+			box(margin, margin, margin+size, margin+size)),
+		fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()),
+		"<< /Producer (go-pixgen) >>",
+	}
+
+	return writePDF(objects), nil
+}
+
+// writePDF serializes the numbered objects with a cross-reference table.
+// The first object is the catalog and the last one the document info.
+func writePDF(objects []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(objects)+1, len(objects), xref)
+
+	return buf.Bytes()
+}
+
+func writeCropMarks(buf *bytes.Buffer, margin, size, bleed float64) {
+	gap := cropMarkGap(bleed)
+	lo, hi := margin, margin+size
+
+	fmt.Fprintf(buf, "0 G %s w\n", pdfNum(cropMarkWidthPt/pointsPerMM))
+	for _, edge := range []float64{lo, hi} {
+		// horizontal marks to the left and right of each horizontal trim edge
+		line(buf, lo-gap-cropMarkLenMM, edge, lo-gap, edge)
+		line(buf, hi+gap, edge, hi+gap+cropMarkLenMM, edge)
+		// vertical marks below and above each vertical trim edge
+		line(buf, edge, lo-gap-cropMarkLenMM, edge, lo-gap)
+		line(buf, edge, hi+gap, edge, hi+gap+cropMarkLenMM)
+	}
+	buf.WriteString("S\n")
+}
+
+func line(buf *bytes.Buffer, x0, y0, x1, y1 float64) {
+	fmt.Fprintf(buf, "%s %s m %s %s l\n", pdfNum(x0), pdfNum(y0), pdfNum(x1), pdfNum(y1))
+}
+
+// cropMarkGap keeps the marks outside the bleed so they are never printed on the piece.
+func cropMarkGap(bleed float64) float64 {
+	if bleed > cropMarkGapMM {
+		return bleed
+	}
+	return cropMarkGapMM
+}
+
+func pdfNum(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 4, 64)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
@@ -0,0 +1,103 @@
+package qrcode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNewPDFStructure(t *testing.T) {
+	pdf, err := NewPDF(PDFOptions{Content: testContent, SizeMM: 40, BleedMM: 3, CropMarks: true})
+	if err != nil {
+		t.Fatalf("render pdf: %v", err)
+	}
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("invalid pdf envelope")
+	}
+
+	// every xref entry must point at its object header
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if startxref == nil {
+		t.Fatalf("missing startxref")
+	}
+	xrefOffset, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(pdf[xrefOffset:], []byte("xref\n")) {
+		t.Fatalf("startxref does not point to xref table")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xrefOffset:], -1)
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 xref entries, got %d", len(entries))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj", i+1); !bytes.HasPrefix(pdf[off:], []byte(want)) {
+			t.Fatalf("xref entry %d does not point to %q", i+1, want)
+		}
+	}
+
+	// 40mm trim + 2*(3mm gap + 5mm marks) = 56mm page
+	pagePt := pdfNum(56 * pointsPerMM)
+	if !bytes.Contains(pdf, []byte("/MediaBox [0 0 "+pagePt+" "+pagePt+"]")) {
+		t.Fatalf("unexpected media box in %s", pdf)
+	}
+	if !bytes.Contains(pdf, []byte("/TrimBox [")) || !bytes.Contains(pdf, []byte("/BleedBox [")) {
+		t.Fatalf("missing trim or bleed box")
+	}
+
+	stream := decodeTestPDFStream(t, pdf)
+	m, err := Encode(testContent, Medium)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if !strings.Contains(stream, " re f\n") {
+		t.Fatalf("missing background fill")
+	}
+	if got, want := strings.Count(stream, " re\n"), len(mergeDarkModules(m.modules)); got != want {
+		t.Fatalf("expected %d rectangles, got %d", want, got)
+	}
+	if got := strings.Count(stream, " l\n"); got != 8 {
+		t.Fatalf("expected 8 crop mark lines, got %d", got)
+	}
+}
+
+func TestNewPDFWithoutMarks(t *testing.T) {
+	pdf, err := NewPDF(PDFOptions{Content: testContent})
+	if err != nil {
+		t.Fatalf("render pdf: %v", err)
+	}
+	pagePt := pdfNum(50 * pointsPerMM)
+	if !bytes.Contains(pdf, []byte("/MediaBox [0 0 "+pagePt+" "+pagePt+"]")) {
+		t.Fatalf("expected 50mm page by default")
+	}
+	if strings.Contains(decodeTestPDFStream(t, pdf), " l\n") {
+		t.Fatalf("unexpected crop marks")
+	}
+
+	if _, err := NewPDF(PDFOptions{Content: testContent, BleedMM: -1}); err == nil {
+		t.Fatalf("expected error for negative bleed")
+	}
+}
+
+func decodeTestPDFStream(t *testing.T, pdf []byte) string {
+	t.Helper()
+	start := bytes.Index(pdf, []byte("stream\n"))
+	end := bytes.Index(pdf, []byte("\nendstream"))
+	if start < 0 || end < 0 {
+		t.Fatalf("missing content stream")
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(pdf[start+len("stream\n") : end]))
+	if err != nil {
+		t.Fatalf("inflate: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("inflate: %v", err)
+	}
+	return string(data)
+}
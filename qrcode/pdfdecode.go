@@ -0,0 +1,411 @@
+package qrcode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/jpeg" // DCTDecode image streams are plain JPEG files
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// pdfModulePixels is the side of the smallest filled rectangle once a page is rasterized.
+	pdfModulePixels = 4
+	// pdfMaxPixels caps the side of a rasterized page.
+	pdfMaxPixels = 6000
+	// pdfMaxStream caps the inflated size of a single stream.
+	pdfMaxStream = 64 << 20
+)
+
+var (
+	pdfLengthRe   = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	pdfImageRe    = regexp.MustCompile(`/Subtype\s*/Image`)
+	pdfNonContent = regexp.MustCompile(`/Type\s*/|/Subtype\s*/|/Length[123]\b`)
+	pdfWidthRe    = regexp.MustCompile(`/Width\s+(\d+)`)
+	pdfHeightRe   = regexp.MustCompile(`/Height\s+(\d+)`)
+	pdfBits8Re    = regexp.MustCompile(`/BitsPerComponent\s+8\b`)
+)
+
+// DecodePDF decodes the QR Codes of a PDF document, page by page. Pages drawn with vector
+// rectangles, as written by NewPDF and NewSheetPDF, are rasterized before decoding;
+// embedded JPEG images and 8-bit gray or RGB Flate images, as in scanned documents, are
+// decoded on their own. Bounds refer to the rasterized page or to the image the code was
+// found in. Encrypted documents and other stream filters are not read. It returns
+// ErrNotFound when nothing can be decoded.
+func DecodePDF(data []byte) ([]Decoded, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, errors.New("qrcode: not a pdf document")
+	}
+
+	var found []Decoded
+	for _, s := range pdfStreams(data) {
+		var img image.Image
+		switch {
+		case pdfImageRe.Match(s.dict):
+			img = pdfImage(s)
+		case !pdfNonContent.Match(s.dict) && s.flate:
+			img = rasterizePDFRects(pdfFilledRects(s.data))
+		}
+		if img == nil {
+			continue
+		}
+		codes, err := Decode(img)
+		if err != nil {
+			continue
+		}
+		found = append(found, codes...)
+	}
+	if len(found) == 0 {
+		return nil, ErrNotFound
+	}
+	return found, nil
+}
+
+// pdfStream is a stream object; data is inflated when the stream is Flate encoded.
+type pdfStream struct {
+	dict  []byte
+	data  []byte
+	flate bool
+	dct   bool
+}
+
+// pdfStreams returns the streams of the document in file order, which is the page order
+// for documents written in one pass. Streams with unsupported filters are skipped.
+func pdfStreams(data []byte) []pdfStream {
+	var streams []pdfStream
+	for pos := 0; ; {
+		i := bytes.Index(data[pos:], []byte("stream"))
+		if i < 0 {
+			return streams
+		}
+		i += pos
+		pos = i + len("stream")
+		if i >= 3 && string(data[i-3:i]) == "end" {
+			continue
+		}
+		objStart := bytes.LastIndex(data[:i], []byte("obj"))
+		if objStart < 0 {
+			continue
+		}
+		dict := data[objStart:i]
+
+		start := pos
+		if start < len(data) && data[start] == '\r' {
+			start++
+		}
+		if start < len(data) && data[start] == '\n' {
+			start++
+		}
+		end := -1
+		// indirect lengths point to another object; fall back to the endstream keyword
+		if m := pdfLengthRe.FindSubmatch(dict); m != nil && len(m[2]) == 0 {
+			if n, err := strconv.Atoi(string(m[1])); err == nil && start+n <= len(data) {
+				end = start + n
+			}
+		}
+		if end < 0 {
+			e := bytes.Index(data[start:], []byte("endstream"))
+			if e < 0 {
+				return streams
+			}
+			end = start + e
+		}
+		pos = end
+		raw := data[start:end]
+
+		s := pdfStream{dict: dict}
+		switch {
+		case bytes.Contains(dict, []byte("/DCTDecode")):
+			s.dct, s.data = true, raw
+		case bytes.Contains(dict, []byte("/FlateDecode")):
+			zr, err := zlib.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				continue
+			}
+			inflated, err := io.ReadAll(io.LimitReader(zr, pdfMaxStream))
+			if err != nil && len(inflated) == 0 {
+				continue
+			}
+			s.flate, s.data = true, inflated
+		case bytes.Contains(dict, []byte("/Filter")):
+			continue
+		default:
+			s.data = raw
+		}
+		streams = append(streams, s)
+	}
+}
+
+// pdfImage decodes an image XObject, or returns nil when its encoding is not supported.
+func pdfImage(s pdfStream) image.Image {
+	if s.dct {
+		img, _, err := image.Decode(bytes.NewReader(s.data))
+		if err != nil {
+			return nil
+		}
+		return img
+	}
+	// predictors and palettes are not undone
+	if !s.flate || bytes.Contains(s.dict, []byte("/DecodeParms")) ||
+		!pdfBits8Re.Match(s.dict) {
+		return nil
+	}
+	w, h := pdfInt(pdfWidthRe, s.dict), pdfInt(pdfHeightRe, s.dict)
+	if w <= 0 || h <= 0 || w > pdfMaxPixels*2 || h > pdfMaxPixels*2 {
+		return nil
+	}
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	switch {
+	case bytes.Contains(s.dict, []byte("/DeviceGray")) && len(s.data) >= w*h:
+		copy(img.Pix, s.data)
+	case bytes.Contains(s.dict, []byte("/DeviceRGB")) && len(s.data) >= 3*w*h:
+		for i := range img.Pix {
+			px := s.data[3*i : 3*i+3]
+			img.Pix[i] = color.GrayModel.Convert(color.RGBA{R: px[0], G: px[1], B: px[2], A: 0xff}).(color.Gray).Y
+		}
+	default:
+		return nil
+	}
+	return img
+}
+
+func pdfInt(re *regexp.Regexp, dict []byte) int {
+	m := re.FindSubmatch(dict)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(string(m[1]))
+	return n
+}
+
+// pdfRect is an axis-aligned rectangle in device space (points, origin at the bottom left).
+type pdfRect struct{ x0, y0, x1, y1 float64 }
+
+// pdfFilledRects runs a content stream and returns the rectangles filled with a dark color.
+// Only the operators needed to place rectangles are interpreted: the graphics state stack,
+// cm, fill colors, re and the fill and path ending operators. Curves, strokes and text are
+// skipped.
+func pdfFilledRects(content []byte) []pdfRect {
+	type state struct {
+		ctm  [6]float64
+		gray float64
+	}
+	gs := state{ctm: [6]float64{1, 0, 0, 1, 0, 0}}
+	var stack []state
+	var operands []float64
+	var path, dark []pdfRect
+
+	arg := func(n int) []float64 {
+		if len(operands) < n {
+			return nil
+		}
+		return operands[len(operands)-n:]
+	}
+
+	lx := pdfLexer{data: content}
+	for {
+		tok, isNum, num, ok := lx.next()
+		if !ok {
+			return dark
+		}
+		if isNum {
+			operands = append(operands, num)
+			continue
+		}
+		switch tok {
+		case "q":
+			stack = append(stack, gs)
+		case "Q":
+			if len(stack) > 0 {
+				gs, stack = stack[len(stack)-1], stack[:len(stack)-1]
+			}
+		case "cm":
+			if a := arg(6); a != nil {
+				c := gs.ctm
+				gs.ctm = [6]float64{
+					a[0]*c[0] + a[1]*c[2], a[0]*c[1] + a[1]*c[3],
+					a[2]*c[0] + a[3]*c[2], a[2]*c[1] + a[3]*c[3],
+					a[4]*c[0] + a[5]*c[2] + c[4], a[4]*c[1] + a[5]*c[3] + c[5],
+				}
+			}
+		case "g":
+			if a := arg(1); a != nil {
+				gs.gray = a[0]
+			}
+		case "rg":
+			if a := arg(3); a != nil {
+				gs.gray = 0.299*a[0] + 0.587*a[1] + 0.114*a[2]
+			}
+		case "k":
+			if a := arg(4); a != nil {
+				gs.gray = 1 - math.Min(1, 0.3*a[0]+0.59*a[1]+0.11*a[2]+a[3])
+			}
+		case "re":
+			if a := arg(4); a != nil {
+				path = append(path, transformPDFRect(gs.ctm, a[0], a[1], a[0]+a[2], a[1]+a[3]))
+			}
+		case "f", "F", "f*", "B", "B*", "b", "b*":
+			if gs.gray < 0.5 {
+				dark = append(dark, path...)
+			}
+			path = path[:0]
+		case "n", "S", "s":
+			path = path[:0]
+		case "ID":
+			lx.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+func transformPDFRect(m [6]float64, x0, y0, x1, y1 float64) pdfRect {
+	r := pdfRect{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, p := range [][2]float64{{x0, y0}, {x1, y0}, {x0, y1}, {x1, y1}} {
+		x := m[0]*p[0] + m[2]*p[1] + m[4]
+		y := m[1]*p[0] + m[3]*p[1] + m[5]
+		r.x0, r.x1 = math.Min(r.x0, x), math.Max(r.x1, x)
+		r.y0, r.y1 = math.Min(r.y0, y), math.Max(r.y1, y)
+	}
+	return r
+}
+
+// rasterizePDFRects draws the rectangles black on white, scaled so the smallest one (a
+// module, as modules are merged into larger runs) is a few pixels wide, with a quiet zone
+// around them. It returns nil when there is nothing to draw.
+func rasterizePDFRects(rects []pdfRect) image.Image {
+	unit := math.Inf(1)
+	bounds := pdfRect{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, r := range rects {
+		if w, h := r.x1-r.x0, r.y1-r.y0; w > 0 && h > 0 {
+			unit = math.Min(unit, math.Min(w, h))
+		}
+		bounds.x0, bounds.x1 = math.Min(bounds.x0, r.x0), math.Max(bounds.x1, r.x1)
+		bounds.y0, bounds.y1 = math.Min(bounds.y0, r.y0), math.Max(bounds.y1, r.y1)
+	}
+	if math.IsInf(unit, 1) {
+		return nil
+	}
+	margin := DefaultQuietZone * unit
+	bounds = pdfRect{bounds.x0 - margin, bounds.y0 - margin, bounds.x1 + margin, bounds.y1 + margin}
+	side := math.Max(bounds.x1-bounds.x0, bounds.y1-bounds.y0)
+	scale := math.Min(pdfModulePixels/unit, pdfMaxPixels/side)
+
+	w := int(math.Ceil((bounds.x1 - bounds.x0) * scale))
+	h := int(math.Ceil((bounds.y1 - bounds.y0) * scale))
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	// rounding both edges keeps adjacent rectangles free of gaps; the y axis is flipped
+	px := func(v, origin float64) int { return int(math.Round((v - origin) * scale)) }
+	for _, r := range rects {
+		x0, x1 := px(r.x0, bounds.x0), px(r.x1, bounds.x0)
+		y0, y1 := h-px(r.y1, bounds.y0), h-px(r.y0, bounds.y0)
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				img.Pix[y*img.Stride+x] = 0
+			}
+		}
+	}
+	return img
+}
+
+// pdfLexer splits a content stream into numbers and operators; names, strings, arrays and
+// dictionaries delimiters and comments are skipped.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func (lx *pdfLexer) next() (tok string, isNum bool, num float64, ok bool) {
+	for lx.pos < len(lx.data) {
+		c := lx.data[lx.pos]
+		switch {
+		case pdfSpace(c):
+			lx.pos++
+		case c == '%':
+			for lx.pos < len(lx.data) && lx.data[lx.pos] != '\n' && lx.data[lx.pos] != '\r' {
+				lx.pos++
+			}
+		case c == '(':
+			lx.skipString()
+		case c == '<' && lx.pos+1 < len(lx.data) && lx.data[lx.pos+1] == '<',
+			c == '>' && lx.pos+1 < len(lx.data) && lx.data[lx.pos+1] == '>':
+			lx.pos += 2
+		case c == '<':
+			if end := bytes.IndexByte(lx.data[lx.pos:], '>'); end >= 0 {
+				lx.pos += end + 1
+			} else {
+				lx.pos = len(lx.data)
+			}
+		case c == '/':
+			lx.pos++
+			lx.word()
+		case pdfDelimiter(c):
+			lx.pos++
+		default:
+			w := lx.word()
+			if v, err := strconv.ParseFloat(w, 64); err == nil {
+				return "", true, v, true
+			}
+			return w, false, 0, true
+		}
+	}
+	return "", false, 0, false
+}
+
+func (lx *pdfLexer) word() string {
+	start := lx.pos
+	for lx.pos < len(lx.data) && !pdfSpace(lx.data[lx.pos]) && !pdfDelimiter(lx.data[lx.pos]) {
+		lx.pos++
+	}
+	return string(lx.data[start:lx.pos])
+}
+
+// skipString skips a literal string, with its nested parentheses and escapes.
+func (lx *pdfLexer) skipString() {
+	depth := 0
+	for ; lx.pos < len(lx.data); lx.pos++ {
+		switch lx.data[lx.pos] {
+		case '\\':
+			lx.pos++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				lx.pos++
+				return
+			}
+		}
+	}
+}
+
+// skipInlineImage skips the binary data of an inline image, up to the EI operator.
+func (lx *pdfLexer) skipInlineImage() {
+	for i := lx.pos; i+2 < len(lx.data); i++ {
+		if pdfSpace(lx.data[i]) && lx.data[i+1] == 'E' && lx.data[i+2] == 'I' &&
+			(i+3 == len(lx.data) || pdfSpace(lx.data[i+3])) {
+			lx.pos = i + 3
+			return
+		}
+	}
+	lx.pos = len(lx.data)
+}
+
+func pdfSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func pdfDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
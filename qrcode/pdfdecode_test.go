@@ -0,0 +1,93 @@
+package qrcode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"testing"
+)
+
+func TestDecodePDF(t *testing.T) {
+	pdf, err := NewPDF(PDFOptions{Content: testContent, SizeMM: 30, BleedMM: 3, CropMarks: true})
+	if err != nil {
+		t.Fatalf("render pdf: %v", err)
+	}
+	got, err := DecodePDF(pdf)
+	if err != nil {
+		t.Fatalf("decode pdf: %v", err)
+	}
+	if len(got) != 1 || got[0].Content != testContent {
+		t.Fatalf("unexpected codes %+v", got)
+	}
+
+	if _, err := DecodePDF([]byte("not a pdf")); err == nil {
+		t.Fatalf("expected non-pdf input to be rejected")
+	}
+	if _, err := DecodePDF([]byte("%PDF-1.4\n")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDecodeSheetPDF(t *testing.T) {
+	var items []SheetItem
+	for i := 0; i < 7; i++ {
+		items = append(items, SheetItem{Content: fmt.Sprintf("%s-%d", testContent, i), Label: fmt.Sprintf("Mesa %d", i)})
+	}
+	// two rows of two columns spill the items over two pages
+	pdf, err := NewSheetPDF(items, SheetOptions{Rows: 2, Columns: 2, CutLines: true})
+	if err != nil {
+		t.Fatalf("render sheet: %v", err)
+	}
+	got, err := DecodePDF(pdf)
+	if err != nil {
+		t.Fatalf("decode sheet: %v", err)
+	}
+	if len(got) != len(items) {
+		t.Fatalf("decoded %d codes, want %d", len(got), len(items))
+	}
+	for i, d := range got {
+		if d.Content != items[i].Content {
+			t.Fatalf("code %d: got %q, want %q", i, d.Content, items[i].Content)
+		}
+	}
+}
+
+func TestDecodePDFImages(t *testing.T) {
+	img := renderTestImage(t, QRCodeOptions{Content: testContent, Size: 300})
+	gray := image.NewGray(img.Bounds())
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			gray.Set(x, y, img.At(x, y))
+		}
+	}
+
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, gray, &jpeg.Options{Quality: 80}); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	var flate bytes.Buffer
+	zw := zlib.NewWriter(&flate)
+	zw.Write(gray.Pix)
+	zw.Close()
+
+	w, h := gray.Bounds().Dx(), gray.Bounds().Dy()
+	cases := map[string][]string{
+		// a scanned page: the image is an XObject drawn by the page content
+		"jpeg": {fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream",
+			w, h, jpg.Len(), jpg.String())},
+		"flate": {fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+			w, h, flate.Len(), flate.String())},
+	}
+	for name, objects := range cases {
+		got, err := DecodePDF(writePDF(objects))
+		if err != nil {
+			t.Fatalf("%s: decode pdf: %v", name, err)
+		}
+		if len(got) != 1 || got[0].Content != testContent {
+			t.Fatalf("%s: unexpected codes %+v", name, got)
+		}
+	}
+}
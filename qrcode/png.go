@@ -0,0 +1,146 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MinContrastRatio is the minimum WCAG contrast ratio accepted between the foreground and
+// background colors. Lower ratios are unreliable on banking app scanners.
+const MinContrastRatio = 3.0
+
+// renderImage draws the matrix, quiet zone included, into a two-color paletted image with
+// square modules of a whole number of pixels, so edges stay crisp. The side is the largest
+// multiple of the module count not above size (at least one pixel per module).
+func renderImage(m *Matrix, size int, fg, bg color.Color) *image.Paletted {
+	modules := m.Dimension()
+	scale := size / modules
+	if scale < 1 {
+		scale = 1
+	}
+	side := modules * scale
+
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{bg, fg})
+	for my := 0; my < modules; my++ {
+		row := img.Pix[my*scale*img.Stride : (my*scale+1)*img.Stride]
+		for mx := 0; mx < modules; mx++ {
+			if !m.Get(mx, my) {
+				continue
+			}
+			for x := mx * scale; x < (mx+1)*scale; x++ {
+				row[x] = 1
+			}
+		}
+		// the remaining pixel rows of the module repeat the first one
+		for y := my*scale + 1; y < (my+1)*scale; y++ {
+			copy(img.Pix[y*img.Stride:(y+1)*img.Stride], row)
+		}
+	}
+	return img
+}
+
+// encodePNG writes img as PNG. Two-color paletted images are stored with a 1-bit palette.
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resolveColors applies the default colors, validates their contrast and returns the
+// palette colors, making the background fully transparent when requested.
+func resolveColors(fg, bg color.Color, transparent bool) (color.Color, color.Color, error) {
+	if fg == nil {
+		fg = color.Black
+	}
+	if bg == nil {
+		bg = color.White
+	}
+	if err := CheckContrast(fg, bg); err != nil {
+		return nil, nil, err
+	}
+	if transparent {
+		c := color.NRGBAModel.Convert(bg).(color.NRGBA)
+		c.A = 0
+		bg = c
+	}
+	return fg, bg, nil
+}
+
+// CheckContrast returns an error when the foreground is not darker than the background
+// or their contrast ratio is below MinContrastRatio. A translucent foreground is
+// evaluated over the background.
+func CheckContrast(fg, bg color.Color) error {
+	fg = composite(fg, bg)
+	bg = composite(bg, color.White)
+	lf, lb := relativeLuminance(fg), relativeLuminance(bg)
+	if lf >= lb {
+		return errors.New("qrcode: foreground must be darker than background")
+	}
+	if ratio := ContrastRatio(fg, bg); ratio < MinContrastRatio {
+		return fmt.Errorf("qrcode: contrast ratio %.2f is below the minimum of %.1f", ratio, MinContrastRatio)
+	}
+	return nil
+}
+
+// ContrastRatio returns the WCAG 2 contrast ratio between two opaque colors (1 to 21).
+func ContrastRatio(a, b color.Color) float64 {
+	la, lb := relativeLuminance(a), relativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+func relativeLuminance(c color.Color) float64 {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	channel := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(n.R) + 0.7152*channel(n.G) + 0.0722*channel(n.B)
+}
+
+// composite blends c over an opaque base color.
+func composite(c, base color.Color) color.Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A == 0xFF {
+		return n
+	}
+	b := color.NRGBAModel.Convert(base).(color.NRGBA)
+	blend := func(v, w uint8) uint8 {
+		return uint8((int(v)*int(n.A) + int(w)*(255-int(n.A))) / 255)
+	}
+	return color.NRGBA{R: blend(n.R, b.R), G: blend(n.G, b.G), B: blend(n.B, b.B), A: 0xFF}
+}
+
+// ParseHexColor parses #RGB, #RRGGBB or #RRGGBBAA colors (the leading # is optional).
+func ParseHexColor(s string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("qrcode: invalid color %q", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("qrcode: invalid color %q", s)
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
@@ -0,0 +1,135 @@
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+)
+
+func decodeTestPNG(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	return img
+}
+
+func TestNewMatchesDefaultRendering(t *testing.T) {
+	got, err := New(QRCodeOptions{Content: testContent, Size: 300})
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	m, err := Encode(testContent, Medium)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	// the side shrinks to a whole number of pixels per module
+	side := 300 / m.Dimension() * m.Dimension()
+	want, err := qrcode.Encode(testContent, qrcode.Medium, side)
+	if err != nil {
+		t.Fatalf("reference png: %v", err)
+	}
+
+	a, b := decodeTestPNG(t, got), decodeTestPNG(t, want)
+	if a.Bounds() != b.Bounds() {
+		t.Fatalf("bounds differ: %v vs %v", a.Bounds(), b.Bounds())
+	}
+	for y := 0; y < a.Bounds().Dy(); y++ {
+		for x := 0; x < a.Bounds().Dx(); x++ {
+			r1, g1, b1, _ := a.At(x, y).RGBA()
+			r2, g2, b2, _ := b.At(x, y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 {
+				t.Fatalf("pixel %d,%d differs", x, y)
+			}
+		}
+	}
+}
+
+func TestNewColorsAndTransparency(t *testing.T) {
+	navy := color.NRGBA{R: 0x1a, G: 0x23, B: 0x7e, A: 0xff}
+	data, err := New(QRCodeOptions{
+		Content:               testContent,
+		Size:                  200,
+		Foreground:            navy,
+		Background:            color.White,
+		TransparentBackground: true,
+		QuietZone:             0,
+		QuietZoneSet:          true,
+	})
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+
+	img := decodeTestPNG(t, data)
+	// without border the top-left finder pattern starts at the first pixel
+	if c := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA); c != navy {
+		t.Fatalf("expected foreground at origin, got %+v", c)
+	}
+	m, _ := Encode(testContent, Medium)
+	px := 200 / m.Size()
+	// module (7,0) is the light separator next to the finder pattern
+	if _, _, _, a := img.At(7*px+px/2, px/2).RGBA(); a != 0 {
+		t.Fatalf("expected transparent background, got alpha %d", a)
+	}
+}
+
+func TestNewOneBitPNG(t *testing.T) {
+	m, err := Encode(testContent, Medium)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	for _, size := range []int{1, 256, 300, 512} {
+		data, err := New(QRCodeOptions{Matrix: m, Size: size})
+		if err != nil {
+			t.Fatalf("render png: %v", err)
+		}
+		// IHDR: width, height, bit depth, color type
+		width := int(data[16])<<24 | int(data[17])<<16 | int(data[18])<<8 | int(data[19])
+		if width%m.Dimension() != 0 || (size >= m.Dimension() && width > size) {
+			t.Fatalf("size %d: width %d is not a multiple of %d modules", size, width, m.Dimension())
+		}
+		if depth, colorType := data[24], data[25]; depth != 1 || colorType != 3 {
+			t.Fatalf("size %d: expected 1-bit palette png, got depth %d type %d", size, depth, colorType)
+		}
+	}
+}
+
+func TestCheckContrast(t *testing.T) {
+	tests := []struct {
+		name string
+		fg   string
+		bg   string
+		ok   bool
+	}{
+		{"black on white", "#000", "#fff", true},
+		{"navy on white", "#1a237e", "#ffffff", true},
+		{"orange on white", "#ffa726", "#ffffff", false},
+		{"inverted", "#ffffff", "#000000", false},
+		{"translucent black", "#00000040", "#ffffff", false},
+	}
+	for _, tc := range tests {
+		fg, err := ParseHexColor(tc.fg)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		bg, err := ParseHexColor(tc.bg)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if err := CheckContrast(fg, bg); (err == nil) != tc.ok {
+			t.Errorf("%s: CheckContrast error = %v, want ok=%v", tc.name, err, tc.ok)
+		}
+	}
+
+	if _, err := New(QRCodeOptions{Content: testContent, Foreground: color.White, Background: color.Black}); err == nil {
+		t.Fatalf("expected inverted colors to be rejected")
+	}
+	if _, err := ParseHexColor("#12345"); err == nil {
+		t.Fatalf("expected invalid color error")
+	}
+}
@@ -1,55 +1,89 @@
 package qrcode
 
 import (
-	"bytes"
-	"errors"
+	"image/color"
 	"strings"
-
-	"github.com/mdp/qrterminal"
-	"github.com/skip2/go-qrcode"
 )
 
 type QRCodeOptions struct {
 	Content string
-	Size    int
+	// Size is the maximum image side in pixels (default 256). The output is rounded down to
+	// a whole number of pixels per module, and never below one pixel per module.
+	Size  int
+	Level ErrorCorrectionLevel
+	// Matrix, when set, is rendered instead of encoding Content; Level is then ignored.
+	Matrix *Matrix
+	// Foreground and Background default to black and white. Pairs failing
+	// CheckContrast are rejected.
+	Foreground color.Color
+	Background color.Color
+	// TransparentBackground keeps light modules transparent for overlaying on artwork.
+	TransparentBackground bool
+	// QuietZone is the border width in modules (0 disables the border); used only when
+	// QuietZoneSet is true, otherwise the matrix quiet zone (DefaultQuietZone) applies.
+	QuietZone    int
+	QuietZoneSet bool
+	// Logo, when set, is drawn at the center and forces the High error correction level.
+	Logo *Logo
 }
 
 func New(options QRCodeOptions) ([]byte, error) {
 	if options.Size == 0 {
 		options.Size = 256
 	}
-	return qrcode.Encode(options.Content, qrcode.Medium, options.Size)
+
+	fg, bg, err := resolveColors(options.Foreground, options.Background, options.TransparentBackground)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := matrixFor(options.Matrix, options.Content, options.Level, options.QuietZone, options.QuietZoneSet, options.Logo)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Logo == nil {
+		return encodePNG(renderImage(m, options.Size, fg, bg))
+	}
+
+	area, cleared, err := prepareLogo(options.Logo, m)
+	if err != nil {
+		return nil, err
+	}
+	img, err := drawLogo(renderImage(cleared, options.Size, fg, bg), options.Logo, area, cleared)
+	if err != nil {
+		return nil, err
+	}
+	return encodePNG(img)
 }
 
+const (
+	asciiBlack = "\033[40m  \033[0m"
+	asciiWhite = "\033[47m  \033[0m"
+)
+
 type ASCIIOptions struct {
-	Content      string
+	Content string
+	// Matrix, when set, is rendered instead of encoding Content; Level is then ignored.
+	Matrix       *Matrix
 	BlackChar    string
 	WhiteChar    string
 	Scale        int
 	QuietZone    bool
 	QuietZoneSet bool
+	Level        ErrorCorrectionLevel
 }
 
+// NewASCII renders the QR Code with one character group per module. The border is a single
+// module unless QuietZone is set, which uses the DefaultQuietZone.
 func NewASCII(opts ASCIIOptions) (string, error) {
-	if strings.TrimSpace(opts.Content) == "" {
-		return "", errors.New("qrcode: content must not be empty")
-	}
-
-	var buf bytes.Buffer
-
-	cfg := qrterminal.Config{
-		Level:  qrterminal.M,
-		Writer: &buf,
+	quiet := 1
+	if opts.QuietZoneSet && opts.QuietZone {
+		quiet = DefaultQuietZone
 	}
-
-	if opts.QuietZoneSet {
-		if opts.QuietZone {
-			cfg.QuietZone = qrterminal.QUIET_ZONE
-		} else {
-			cfg.QuietZone = 1
-		}
-	} else {
-		cfg.QuietZone = 1
+	m, err := matrixFor(opts.Matrix, opts.Content, opts.Level, quiet, true, nil)
+	if err != nil {
+		return "", err
 	}
 
 	scale := opts.Scale
@@ -59,17 +93,28 @@ func NewASCII(opts ASCIIOptions) (string, error) {
 
 	black := opts.BlackChar
 	if black == "" {
-		black = qrterminal.BLACK
+		black = asciiBlack
 	}
 	white := opts.WhiteChar
 	if white == "" {
-		white = qrterminal.WHITE
+		white = asciiWhite
 	}
+	black = strings.Repeat(black, scale)
+	white = strings.Repeat(white, scale)
 
-	cfg.BlackChar = strings.Repeat(black, scale)
-	cfg.WhiteChar = strings.Repeat(white, scale)
-
-	qrterminal.GenerateWithConfig(opts.Content, cfg)
-
-	return strings.TrimRight(buf.String(), "\n"), nil
+	var buf strings.Builder
+	dim := m.Dimension()
+	for y := 0; y < dim; y++ {
+		if y > 0 {
+			buf.WriteByte('\n')
+		}
+		for x := 0; x < dim; x++ {
+			if m.Get(x, y) {
+				buf.WriteString(black)
+			} else {
+				buf.WriteString(white)
+			}
+		}
+	}
+	return buf.String(), nil
 }
@@ -0,0 +1,39 @@
+package qrcode
+
+// moduleRect is a rectangle of dark modules, in module coordinates.
+type moduleRect struct {
+	x, y, w, h int
+}
+
+// mergeDarkModules covers the dark modules of bitmap with non-overlapping rectangles by
+// merging horizontal runs and stacking identical runs of consecutive rows. Vector renderers
+// use it to keep their output small.
+func mergeDarkModules(bitmap [][]bool) []moduleRect {
+	active := map[[2]int]int{}
+	var rects []moduleRect
+
+	for y, row := range bitmap {
+		next := map[[2]int]int{}
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			key := [2]int{start, x - start}
+			if idx, ok := active[key]; ok {
+				rects[idx].h++
+				next[key] = idx
+				continue
+			}
+			next[key] = len(rects)
+			rects = append(rects, moduleRect{x: start, y: y, w: x - start, h: 1})
+		}
+		active = next
+	}
+
+	return rects
+}
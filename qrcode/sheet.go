@@ -0,0 +1,321 @@
+package qrcode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// A4WidthMM and A4HeightMM are the default sheet size.
+	A4WidthMM  = 210.0
+	A4HeightMM = 297.0
+
+	// DefaultSheetRows and DefaultSheetColumns give 12 codes per page.
+	DefaultSheetRows    = 4
+	DefaultSheetColumns = 3
+	// DefaultSheetMarginMM is the page margin used when SheetOptions.MarginMM is zero.
+	DefaultSheetMarginMM = 10.0
+	// DefaultSheetLabelMM is the label font size used when SheetOptions.LabelSizeMM is zero.
+	DefaultSheetLabelMM = 4.0
+
+	sheetPaddingMM = 4.0
+	sheetMinQRMM   = 10.0
+)
+
+// SheetItem is one cell of a sheet: the QR Code content and the label printed below it.
+type SheetItem struct {
+	Content string
+	Label   string
+}
+
+// SheetOptions configures the grid of NewSheetPDF and NewSheetSVG.
+type SheetOptions struct {
+	// PageWidthMM and PageHeightMM default to A4 portrait.
+	PageWidthMM  float64
+	PageHeightMM float64
+	// Rows and Columns set the grid of each page (default DefaultSheetRows x
+	// DefaultSheetColumns). Items beyond one page continue on the next.
+	Rows    int
+	Columns int
+	// MarginMM is the blank border of the page (default DefaultSheetMarginMM).
+	MarginMM float64
+	// LabelSizeMM is the label font size; long labels are shrunk to the cell width.
+	LabelSizeMM float64
+	// CutLines draws dashed lines along the cell borders.
+	CutLines bool
+	Level    ErrorCorrectionLevel
+}
+
+// sheetLayout holds the resolved page geometry, in millimeters from the top-left corner.
+type sheetLayout struct {
+	width, height float64
+	rows, columns int
+	margin        float64
+	cellW, cellH  float64
+	qrSide, label float64
+	hasLabels     bool
+	matrices      []*Matrix
+}
+
+func (l sheetLayout) perPage() int { return l.rows * l.columns }
+
+func (l sheetLayout) pages() int {
+	return (len(l.matrices) + l.perPage() - 1) / l.perPage()
+}
+
+// cell returns the top-left corner of the i-th cell of its page.
+func (l sheetLayout) cell(i int) (float64, float64) {
+	i %= l.perPage()
+	return l.margin + float64(i%l.columns)*l.cellW, l.margin + float64(i/l.columns)*l.cellH
+}
+
+// qrOrigin returns the top-left corner of the QR Code of the i-th item, quiet zone included.
+func (l sheetLayout) qrOrigin(i int) (float64, float64) {
+	x, y := l.cell(i)
+	return x + (l.cellW-l.qrSide)/2, y + sheetPaddingMM
+}
+
+// labelBaseline returns the center and baseline of the label of the i-th item.
+func (l sheetLayout) labelBaseline(i int) (float64, float64) {
+	x, y := l.cell(i)
+	return x + l.cellW/2, y + sheetPaddingMM + l.qrSide + l.label
+}
+
+func newSheetLayout(items []SheetItem, opts SheetOptions) (sheetLayout, error) {
+	if len(items) == 0 {
+		return sheetLayout{}, errors.New("qrcode: sheet requires at least one item")
+	}
+	if opts.PageWidthMM < 0 || opts.PageHeightMM < 0 || opts.MarginMM < 0 || opts.LabelSizeMM < 0 ||
+		opts.Rows < 0 || opts.Columns < 0 {
+		return sheetLayout{}, errors.New("qrcode: sheet options must not be negative")
+	}
+	l := sheetLayout{
+		width:   orDefaultFloat(opts.PageWidthMM, A4WidthMM),
+		height:  orDefaultFloat(opts.PageHeightMM, A4HeightMM),
+		rows:    opts.Rows,
+		columns: opts.Columns,
+		margin:  orDefaultFloat(opts.MarginMM, DefaultSheetMarginMM),
+		label:   orDefaultFloat(opts.LabelSizeMM, DefaultSheetLabelMM),
+	}
+	if l.rows == 0 {
+		l.rows = DefaultSheetRows
+	}
+	if l.columns == 0 {
+		l.columns = DefaultSheetColumns
+	}
+	l.cellW = (l.width - 2*l.margin) / float64(l.columns)
+	l.cellH = (l.height - 2*l.margin) / float64(l.rows)
+
+	for _, item := range items {
+		m, err := Encode(item.Content, opts.Level)
+		if err != nil {
+			return sheetLayout{}, err
+		}
+		l.matrices = append(l.matrices, m)
+		if strings.TrimSpace(item.Label) != "" {
+			l.hasLabels = true
+		}
+	}
+
+	reserved := 2 * sheetPaddingMM
+	if l.hasLabels {
+		// the label line plus room for descenders
+		reserved += l.label * 1.3
+	}
+	l.qrSide = math.Min(l.cellW-2*sheetPaddingMM, l.cellH-reserved)
+	if l.qrSide < sheetMinQRMM {
+		return sheetLayout{}, fmt.Errorf("qrcode: %dx%d grid leaves %.1fmm per code, below the %.0fmm minimum",
+			l.rows, l.columns, l.qrSide, sheetMinQRMM)
+	}
+	return l, nil
+}
+
+func orDefaultFloat(v, def float64) float64 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+// NewSheetPDF lays out one QR Code per item on a grid, with its label centered below, and
+// returns a PDF with as many pages as needed. Codes are vector rectangles; labels use the
+// standard Helvetica font, so nothing is embedded.
+func NewSheetPDF(items []SheetItem, opts SheetOptions) ([]byte, error) {
+	l, err := newSheetLayout(items, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := l.pages()
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // page tree, filled in once page object numbers are known
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+	kids := make([]string, 0, pages)
+	media := fmt.Sprintf("[0 0 %s %s]", pdfNum(l.width*pointsPerMM), pdfNum(l.height*pointsPerMM))
+
+	for page := 0; page < pages; page++ {
+		var content bytes.Buffer
+		// coordinates are in mm with the origin at the bottom-left corner
+		content.WriteString("0 g\n")
+		for i := page * l.perPage(); i < len(items) && i < (page+1)*l.perPage(); i++ {
+			m := l.matrices[i]
+			module := l.qrSide / float64(m.Dimension())
+			x0, y0 := l.qrOrigin(i)
+			for _, r := range mergeDarkModules(m.modules) {
+				x := x0 + float64(r.x+m.QuietZone())*module
+				y := l.height - y0 - float64(r.y+m.QuietZone()+r.h)*module
+				fmt.Fprintf(&content, "%s %s %s %s re\n",
+					pdfNum(x), pdfNum(y), pdfNum(float64(r.w)*module), pdfNum(float64(r.h)*module))
+			}
+			content.WriteString("f\n")
+
+			if label := strings.TrimSpace(items[i].Label); label != "" {
+				text := winAnsi(label)
+				size := l.label
+				// shrink labels wider than the cell
+				if w := helveticaWidth(text) * size; w > l.cellW-2*sheetPaddingMM {
+					size *= (l.cellW - 2*sheetPaddingMM) / w
+				}
+				cx, baseline := l.labelBaseline(i)
+				fmt.Fprintf(&content, "BT /F1 %s Tf %s %s Td (%s) Tj ET\n", pdfNum(size),
+					pdfNum(cx-helveticaWidth(text)*size/2), pdfNum(l.height-baseline), pdfString(text))
+			}
+		}
+		if opts.CutLines {
+			fmt.Fprintf(&content, "0.6 G %s w [1 1] 0 d\n", pdfNum(cropMarkWidthPt/pointsPerMM))
+			for c := 0; c <= l.columns; c++ {
+				x := l.margin + float64(c)*l.cellW
+				line(&content, x, l.margin, x, l.height-l.margin)
+			}
+			for r := 0; r <= l.rows; r++ {
+				y := l.margin + float64(r)*l.cellH
+				line(&content, l.margin, y, l.width-l.margin, y)
+			}
+			content.WriteString("S\n")
+		}
+
+		stream := fmt.Sprintf("q %s 0 0 %s 0 0 cm\n%sQ\n", pdfNum(pointsPerMM), pdfNum(pointsPerMM), content.String())
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write([]byte(stream)); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+
+		pageObj := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageObj))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox %s /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				media, pageObj+1),
+			fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pages)
+	objects = append(objects, "<< /Producer (go-pixgen) >>")
+
+	return writePDF(objects), nil
+}
+
+// NewSheetSVG lays out the items like NewSheetPDF and returns one SVG document per page,
+// sized in millimeters.
+func NewSheetSVG(items []SheetItem, opts SheetOptions) ([][]byte, error) {
+	l, err := newSheetLayout(items, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs [][]byte
+	for page := 0; page < l.pages(); page++ {
+		var buf bytes.Buffer
+		buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+		fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%smm" height="%smm" viewBox="0 0 %s %s">`,
+			pdfNum(l.width), pdfNum(l.height), pdfNum(l.width), pdfNum(l.height))
+		fmt.Fprintf(&buf, `<rect width="%s" height="%s" fill="#ffffff"/>`, pdfNum(l.width), pdfNum(l.height))
+
+		for i := page * l.perPage(); i < len(items) && i < (page+1)*l.perPage(); i++ {
+			m := l.matrices[i]
+			x, y := l.qrOrigin(i)
+			fmt.Fprintf(&buf, `<path fill="#000000" shape-rendering="crispEdges" transform="translate(%s %s) scale(%s)" d="`,
+				pdfNum(x), pdfNum(y), pdfNum(l.qrSide/float64(m.Dimension())))
+			writeSVGPath(&buf, m.modules, m.QuietZone())
+			buf.WriteString(`"/>`)
+
+			if label := strings.TrimSpace(items[i].Label); label != "" {
+				cx, baseline := l.labelBaseline(i)
+				fmt.Fprintf(&buf, `<text x="%s" y="%s" font-family="Helvetica, Arial, sans-serif" font-size="%s" text-anchor="middle" textLength="%s" lengthAdjust="spacingAndGlyphs">`,
+					pdfNum(cx), pdfNum(baseline), pdfNum(l.label), pdfNum(math.Min(helveticaWidth(winAnsi(label))*l.label, l.cellW-2*sheetPaddingMM)))
+				if err := xml.EscapeText(&buf, []byte(label)); err != nil {
+					return nil, err
+				}
+				buf.WriteString(`</text>`)
+			}
+		}
+		if opts.CutLines {
+			buf.WriteString(`<path fill="none" stroke="#999999" stroke-width="0.1" stroke-dasharray="1 1" d="`)
+			for c := 0; c <= l.columns; c++ {
+				x := l.margin + float64(c)*l.cellW
+				fmt.Fprintf(&buf, "M%s %sV%s", pdfNum(x), pdfNum(l.margin), pdfNum(l.height-l.margin))
+			}
+			for r := 0; r <= l.rows; r++ {
+				y := l.margin + float64(r)*l.cellH
+				fmt.Fprintf(&buf, "M%s %sH%s", pdfNum(l.margin), pdfNum(y), pdfNum(l.width-l.margin))
+			}
+			buf.WriteString(`"/>`)
+		}
+		buf.WriteString("</svg>\n")
+		docs = append(docs, buf.Bytes())
+	}
+	return docs, nil
+}
+
+// winAnsi converts text to WinAnsiEncoding (Latin-1 for accented letters); other runes
+// become '?'.
+func winAnsi(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r < 0x20 || (r > 0x7e && r < 0xa0) || r > 0xff {
+			r = '?'
+		}
+		b = append(b, byte(r))
+	}
+	return string(b)
+}
+
+// pdfString escapes a literal string for a content stream.
+func pdfString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(s)
+}
+
+// helveticaASCII holds the Helvetica advance widths (1/1000 em) of the printable ASCII
+// characters, from the standard font metrics.
+var helveticaASCII = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space - /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 - ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ - O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P - _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` - o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p - ~
+}
+
+// helveticaWidth returns the width of WinAnsi text in em. Latin-1 letters are measured as
+// an average lowercase letter.
+func helveticaWidth(s string) float64 {
+	var w int
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 0x20 && c <= 0x7e {
+			w += helveticaASCII[c-0x20]
+		} else {
+			w += 556
+		}
+	}
+	return float64(w) / 1000
+}
@@ -0,0 +1,143 @@
+package qrcode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testSheetItems(n int) []SheetItem {
+	items := make([]SheetItem, n)
+	for i := range items {
+		items[i] = SheetItem{Content: fmt.Sprintf("%s/mesa-%02d", testContent, i+1), Label: fmt.Sprintf("Mesa %d", i+1)}
+	}
+	return items
+}
+
+// rasterizeTestSheet fills the rectangles of a sheet content stream (mm units, origin at the
+// bottom) into an image at pxPerMM.
+func rasterizeTestSheet(t *testing.T, stream string, widthMM, heightMM, pxPerMM float64) image.Image {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, int(widthMM*pxPerMM), int(heightMM*pxPerMM)))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for _, line := range strings.Split(stream, "\n") {
+		f := strings.Fields(line)
+		if len(f) != 5 || f[4] != "re" {
+			continue
+		}
+		var v [4]float64
+		for i := range v {
+			v[i], _ = strconv.ParseFloat(f[i], 64)
+		}
+		x0, x1 := int(v[0]*pxPerMM+0.5), int((v[0]+v[2])*pxPerMM+0.5)
+		y0, y1 := int((heightMM-v[1]-v[3])*pxPerMM+0.5), int((heightMM-v[1])*pxPerMM+0.5)
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				img.SetGray(x, y, color.Gray{})
+			}
+		}
+	}
+	return img
+}
+
+func decodeTestPDFStreams(t *testing.T, pdf []byte) []string {
+	t.Helper()
+	var streams []string
+	for rest := pdf; ; {
+		start := bytes.Index(rest, []byte("stream\n"))
+		if start < 0 {
+			return streams
+		}
+		rest = rest[start+len("stream\n"):]
+		end := bytes.Index(rest, []byte("\nendstream"))
+		zr, err := zlib.NewReader(bytes.NewReader(rest[:end]))
+		if err != nil {
+			t.Fatalf("inflate: %v", err)
+		}
+		data, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("inflate: %v", err)
+		}
+		streams = append(streams, string(data))
+		rest = rest[end+len("\nendstream"):]
+	}
+}
+
+func TestNewSheetPDF(t *testing.T) {
+	items := testSheetItems(14)
+	pdf, err := NewSheetPDF(items, SheetOptions{CutLines: true})
+	if err != nil {
+		t.Fatalf("render sheet: %v", err)
+	}
+	if !bytes.Contains(pdf, []byte("/Count 2")) {
+		t.Fatalf("expected 14 items to take two A4 pages of 12")
+	}
+
+	streams := decodeTestPDFStreams(t, pdf)
+	if len(streams) != 2 {
+		t.Fatalf("expected 2 content streams, got %d", len(streams))
+	}
+	if !strings.Contains(streams[0], "(Mesa 1) Tj") || !strings.Contains(streams[1], "(Mesa 14) Tj") {
+		t.Fatalf("expected labels on their pages")
+	}
+	// 3 columns + 1 and 4 rows + 1 cut lines
+	if got := strings.Count(streams[0], " l\n"); got != 9 {
+		t.Fatalf("expected 9 cut lines, got %d", got)
+	}
+
+	// the codes are where the layout puts them, in reading order
+	codes, err := Decode(rasterizeTestSheet(t, streams[0], A4WidthMM, A4HeightMM, 5))
+	if err != nil {
+		t.Fatalf("decode sheet: %v", err)
+	}
+	if len(codes) != 12 {
+		t.Fatalf("expected 12 codes on the first page, got %d", len(codes))
+	}
+	for i, c := range codes {
+		if c.Content != items[i].Content {
+			t.Fatalf("code %d: expected %q, got %q", i, items[i].Content, c.Content)
+		}
+	}
+}
+
+func TestNewSheetSVG(t *testing.T) {
+	items := testSheetItems(4)
+	items[0].Label = "Mesa <1> & Café"
+	docs, err := NewSheetSVG(items, SheetOptions{Rows: 2, Columns: 2, MarginMM: 5})
+	if err != nil {
+		t.Fatalf("render sheet: %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("expected one page, got %d", len(docs))
+	}
+	doc := string(docs[0])
+	if !strings.Contains(doc, `width="210mm" height="297mm"`) {
+		t.Fatalf("expected an A4 document")
+	}
+	if got := strings.Count(doc, "<path"); got != 4 {
+		t.Fatalf("expected 4 codes without cut lines, got %d paths", got)
+	}
+	if !strings.Contains(doc, "Mesa &lt;1&gt; &amp; Café</text>") {
+		t.Fatalf("expected an escaped label")
+	}
+}
+
+func TestSheetLayoutErrors(t *testing.T) {
+	if _, err := NewSheetPDF(nil, SheetOptions{}); err == nil {
+		t.Fatalf("expected an empty sheet to be rejected")
+	}
+	if _, err := NewSheetPDF(testSheetItems(1), SheetOptions{Rows: 20, Columns: 20}); err == nil {
+		t.Fatalf("expected cells too small for a code to be rejected")
+	}
+	if _, err := NewSheetSVG(testSheetItems(1), SheetOptions{MarginMM: -1}); err == nil {
+		t.Fatalf("expected a negative margin to be rejected")
+	}
+}
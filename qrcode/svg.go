@@ -0,0 +1,94 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// DefaultQuietZone is the quiet zone width, in modules, required by ISO/IEC 18004.
+const DefaultQuietZone = 4
+
+// SVGOptions configures the vector SVG renderer.
+type SVGOptions struct {
+	Content string
+	// Matrix, when set, is rendered instead of encoding Content; Level is then ignored.
+	Matrix *Matrix
+	// ModuleSize is the rendered width of a module in pixels (default 8).
+	ModuleSize int
+	// QuietZone is the border width in modules; used only when QuietZoneSet is true,
+	// otherwise DefaultQuietZone applies.
+	QuietZone    int
+	QuietZoneSet bool
+	Level        ErrorCorrectionLevel
+	// Logo, when set, is embedded at the center and forces the High error correction level.
+	Logo *Logo
+}
+
+// NewSVG renders the QR Code as a standalone SVG document. Adjacent dark modules are merged
+// into rectangles so the path stays small, and the viewBox is expressed in modules so the
+// image scales without blurring.
+func NewSVG(opts SVGOptions) ([]byte, error) {
+	m, err := matrixFor(opts.Matrix, opts.Content, opts.Level, opts.QuietZone, opts.QuietZoneSet, opts.Logo)
+	if err != nil {
+		return nil, err
+	}
+	quiet := m.QuietZone()
+
+	moduleSize := opts.ModuleSize
+	if moduleSize <= 0 {
+		moduleSize = 8
+	}
+
+	var logoTag string
+	if opts.Logo != nil {
+		area, cleared, err := prepareLogo(opts.Logo, m)
+		if err != nil {
+			return nil, err
+		}
+		uri, err := logoDataURI(opts.Logo)
+		if err != nil {
+			return nil, err
+		}
+		m = cleared
+		inner := area.size - 2*area.padding
+		logoTag = fmt.Sprintf(`<image x="%d" y="%d" width="%d" height="%d" preserveAspectRatio="xMidYMid meet" xlink:href="%s"/>`,
+			quiet+area.start+area.padding, quiet+area.start+area.padding, inner, inner, uri)
+	}
+
+	dim := m.Dimension()
+	pixels := dim * moduleSize
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		pixels, pixels, dim, dim)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#ffffff"/>`, dim, dim)
+	buf.WriteString(`<path fill="#000000" d="`)
+	writeSVGPath(&buf, m.modules, quiet)
+	buf.WriteString(`"/>`)
+	buf.WriteString(logoTag)
+	buf.WriteString("</svg>\n")
+
+	return buf.Bytes(), nil
+}
+
+// writeSVGPath emits one closed subpath per merged rectangle of dark modules.
+func writeSVGPath(buf *bytes.Buffer, bitmap [][]bool, offset int) {
+	for i, r := range mergeDarkModules(bitmap) {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteByte('M')
+		buf.WriteString(strconv.Itoa(r.x + offset))
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.Itoa(r.y + offset))
+		buf.WriteByte('h')
+		buf.WriteString(strconv.Itoa(r.w))
+		buf.WriteByte('v')
+		buf.WriteString(strconv.Itoa(r.h))
+		buf.WriteString("h-")
+		buf.WriteString(strconv.Itoa(r.w))
+		buf.WriteByte('z')
+	}
+}
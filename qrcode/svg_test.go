@@ -0,0 +1,82 @@
+package qrcode
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+)
+
+const testContent = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+var svgRectPattern = regexp.MustCompile(`M(\d+) (\d+)h(\d+)v(\d+)h-(\d+)z`)
+
+func TestNewSVGMatchesBitmap(t *testing.T) {
+	svg, err := NewSVG(SVGOptions{Content: testContent, ModuleSize: 10, QuietZone: 2, QuietZoneSet: true})
+	if err != nil {
+		t.Fatalf("render svg: %v", err)
+	}
+
+	code, err := qrcode.New(testContent, qrcode.Medium)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	code.DisableBorder = true
+	bitmap := code.Bitmap()
+	dim := len(bitmap) + 4
+
+	viewBox := []byte(`viewBox="0 0 ` + strconv.Itoa(dim) + " " + strconv.Itoa(dim) + `"`)
+	if !bytes.Contains(svg, viewBox) {
+		t.Fatalf("expected %s in svg", viewBox)
+	}
+	if !bytes.Contains(svg, []byte(`width="`+strconv.Itoa(dim*10)+`"`)) {
+		t.Fatalf("expected width of %d pixels", dim*10)
+	}
+
+	grid := make([][]bool, dim)
+	for i := range grid {
+		grid[i] = make([]bool, dim)
+	}
+	matches := svgRectPattern.FindAllSubmatch(svg, -1)
+	for _, m := range matches {
+		x, _ := strconv.Atoi(string(m[1]))
+		y, _ := strconv.Atoi(string(m[2]))
+		w, _ := strconv.Atoi(string(m[3]))
+		h, _ := strconv.Atoi(string(m[4]))
+		for dy := 0; dy < h; dy++ {
+			for dx := 0; dx < w; dx++ {
+				if grid[y+dy][x+dx] {
+					t.Fatalf("overlapping rectangles at %d,%d", x+dx, y+dy)
+				}
+				grid[y+dy][x+dx] = true
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < dim; y++ {
+		for x := 0; x < dim; x++ {
+			want := false
+			if y >= 2 && y < dim-2 && x >= 2 && x < dim-2 {
+				want = bitmap[y-2][x-2]
+			}
+			if grid[y][x] != want {
+				t.Fatalf("module %d,%d: got %v want %v", x, y, grid[y][x], want)
+			}
+			if want {
+				dark++
+			}
+		}
+	}
+	if len(matches) >= dark {
+		t.Fatalf("expected merged rectangles, got %d for %d dark modules", len(matches), dark)
+	}
+}
+
+func TestNewSVGEmptyContent(t *testing.T) {
+	if _, err := NewSVG(SVGOptions{}); err == nil {
+		t.Fatalf("expected error for empty content")
+	}
+}
@@ -0,0 +1,139 @@
+package qrcode
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultTerminalModuleSize is the module size, in pixels, of terminal images.
+	DefaultTerminalModuleSize = 4
+
+	// kittyChunkSize is the largest base64 payload the kitty protocol accepts per escape.
+	kittyChunkSize = 4096
+)
+
+// TerminalImageOptions configures the Sixel and Kitty graphics renderers.
+type TerminalImageOptions struct {
+	Content string
+	// Matrix, when set, is rendered instead of encoding Content; Level is then ignored.
+	Matrix *Matrix
+	Level  ErrorCorrectionLevel
+	// QuietZone is the border width in modules; used only when QuietZoneSet is true,
+	// otherwise the matrix quiet zone (DefaultQuietZone) applies.
+	QuietZone    int
+	QuietZoneSet bool
+	// ModuleSize is the side of a module in pixels (default DefaultTerminalModuleSize).
+	ModuleSize int
+}
+
+func (o TerminalImageOptions) matrix() (*Matrix, int, error) {
+	m, err := matrixFor(o.Matrix, o.Content, o.Level, o.QuietZone, o.QuietZoneSet, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	size := o.ModuleSize
+	if size < 0 {
+		return nil, 0, errors.New("qrcode: module size must not be negative")
+	}
+	if size == 0 {
+		size = DefaultTerminalModuleSize
+	}
+	return m, size, nil
+}
+
+// NewSixel renders the QR Code as a Sixel image (DEC graphics), supported by xterm -ti
+// vt340, mlterm, foot, WezTerm and others. Each band of six pixel rows is painted once per
+// color with run-length encoding.
+func NewSixel(opts TerminalImageOptions) (string, error) {
+	m, moduleSize, err := opts.matrix()
+	if err != nil {
+		return "", err
+	}
+	px := m.Dimension() * moduleSize
+	dark := func(x, y int) bool { return m.Get(x/moduleSize, y/moduleSize) }
+
+	var buf strings.Builder
+	// P2=1: pixels not painted keep the terminal background; both colors are painted anyway
+	buf.WriteString("\x1bP0;1;0q")
+	fmt.Fprintf(&buf, "\"1;1;%d;%d", px, px)
+	buf.WriteString("#0;2;100;100;100#1;2;0;0;0")
+	for band := 0; band < px; band += 6 {
+		for c, want := range []bool{false, true} {
+			if c > 0 {
+				buf.WriteByte('$')
+			}
+			fmt.Fprintf(&buf, "#%d", c)
+			var run int
+			var last byte
+			for x := 0; x < px; x++ {
+				var bits byte
+				for i := 0; i < 6; i++ {
+					if band+i < px && dark(x, band+i) == want {
+						bits |= 1 << i
+					}
+				}
+				ch := 63 + bits
+				if run > 0 && ch != last {
+					writeSixelRun(&buf, last, run)
+					run = 0
+				}
+				last = ch
+				run++
+			}
+			writeSixelRun(&buf, last, run)
+		}
+		buf.WriteByte('-')
+	}
+	buf.WriteString("\x1b\\")
+	return buf.String(), nil
+}
+
+func writeSixelRun(buf *strings.Builder, ch byte, n int) {
+	if n > 3 {
+		buf.WriteByte('!')
+		buf.WriteString(strconv.Itoa(n))
+		buf.WriteByte(ch)
+		return
+	}
+	for i := 0; i < n; i++ {
+		buf.WriteByte(ch)
+	}
+}
+
+// NewKitty renders the QR Code with the kitty terminal graphics protocol (kitty, WezTerm,
+// Ghostty, Konsole): a PNG transmitted and displayed in 4096-byte base64 chunks.
+func NewKitty(opts TerminalImageOptions) (string, error) {
+	m, moduleSize, err := opts.matrix()
+	if err != nil {
+		return "", err
+	}
+	png, err := encodePNG(renderImage(m, m.Dimension()*moduleSize, color.Black, color.White))
+	if err != nil {
+		return "", err
+	}
+	data := base64.StdEncoding.EncodeToString(png)
+
+	var buf strings.Builder
+	for i := 0; i < len(data); i += kittyChunkSize {
+		end := i + kittyChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		more := 0
+		if end < len(data) {
+			more = 1
+		}
+		buf.WriteString("\x1b_G")
+		if i == 0 {
+			// transmit and display a PNG, without replies from the terminal
+			buf.WriteString("a=T,f=100,q=2,")
+		}
+		fmt.Fprintf(&buf, "m=%d;%s\x1b\\", more, data[i:end])
+	}
+	return buf.String(), nil
+}
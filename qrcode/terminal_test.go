@@ -0,0 +1,136 @@
+package qrcode
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// decodeTestSixel paints the sixel data of a two-color image into a pixel grid of dark
+// (register 1) pixels.
+func decodeTestSixel(t *testing.T, s string, size int) [][]bool {
+	t.Helper()
+	if !strings.HasPrefix(s, "\x1bP") || !strings.HasSuffix(s, "\x1b\\") {
+		t.Fatalf("missing sixel DCS envelope")
+	}
+	body := s[strings.IndexByte(s, 'q')+1 : len(s)-2]
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+
+	x, band, reg := 0, 0, 0
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case c == '"':
+			for i+1 < len(body) && (body[i+1] == ';' || (body[i+1] >= '0' && body[i+1] <= '9')) {
+				i++
+			}
+		case c == '#':
+			j := i + 1
+			for j < len(body) && body[j] >= '0' && body[j] <= '9' {
+				j++
+			}
+			reg, _ = strconv.Atoi(body[i+1 : j])
+			// skip color definitions
+			for j < len(body) && (body[j] == ';' || (body[j] >= '0' && body[j] <= '9')) {
+				j++
+			}
+			i = j - 1
+		case c == '$':
+			x = 0
+		case c == '-':
+			x, band = 0, band+6
+		case c == '!' || (c >= 63 && c <= 126):
+			n := 1
+			if c == '!' {
+				j := i + 1
+				for body[j] >= '0' && body[j] <= '9' {
+					j++
+				}
+				n, _ = strconv.Atoi(body[i+1 : j])
+				i, c = j, body[j]
+			}
+			for k := 0; k < n; k++ {
+				for b := 0; b < 6; b++ {
+					if (c-63)&(1<<b) != 0 && band+b < size && reg == 1 {
+						grid[band+b][x] = true
+					}
+				}
+				x++
+			}
+		}
+	}
+	return grid
+}
+
+func TestNewSixel(t *testing.T) {
+	m, err := Encode(testContent, Medium)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	out, err := NewSixel(TerminalImageOptions{Matrix: m, ModuleSize: 3})
+	if err != nil {
+		t.Fatalf("render sixel: %v", err)
+	}
+	size := m.Dimension() * 3
+	if !strings.Contains(out, "\"1;1;"+strconv.Itoa(size)+";"+strconv.Itoa(size)) {
+		t.Fatalf("missing raster attributes for %dpx", size)
+	}
+	grid := decodeTestSixel(t, out, size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if grid[y][x] != m.Get(x/3, y/3) {
+				t.Fatalf("pixel %d,%d differs from matrix", x, y)
+			}
+		}
+	}
+}
+
+func TestNewKitty(t *testing.T) {
+	m, err := Encode(testContent, High)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	out, err := NewKitty(TerminalImageOptions{Matrix: m, ModuleSize: 8})
+	if err != nil {
+		t.Fatalf("render kitty: %v", err)
+	}
+
+	chunks := strings.Split(strings.TrimSuffix(out, "\x1b\\"), "\x1b\\")
+	var data strings.Builder
+	for i, chunk := range chunks {
+		if !strings.HasPrefix(chunk, "\x1b_G") {
+			t.Fatalf("chunk %d is not a graphics escape", i)
+		}
+		keys, payload := chunk[3:strings.IndexByte(chunk, ';')], chunk[strings.IndexByte(chunk, ';')+1:]
+		if i == 0 && !strings.HasPrefix(keys, "a=T,f=100") {
+			t.Fatalf("first chunk must transmit a png, got %q", keys)
+		}
+		last := i == len(chunks)-1
+		if strings.HasSuffix(keys, "m=1") == last {
+			t.Fatalf("chunk %d has wrong continuation flag %q", i, keys)
+		}
+		if len(payload) > kittyChunkSize {
+			t.Fatalf("chunk %d exceeds %d bytes", i, kittyChunkSize)
+		}
+		data.WriteString(payload)
+	}
+	raw, err := base64.StdEncoding.DecodeString(data.String())
+	if err != nil {
+		t.Fatalf("decode base64: %v", err)
+	}
+	img := decodeTestPNG(t, raw)
+	if img.Bounds().Dx() != m.Dimension()*8 {
+		t.Fatalf("unexpected image width %d", img.Bounds().Dx())
+	}
+	for y := 0; y < m.Dimension(); y++ {
+		for x := 0; x < m.Dimension(); x++ {
+			r, _, _, _ := img.At(x*8+4, y*8+4).RGBA()
+			if (r == 0) != m.Get(x, y) {
+				t.Fatalf("module %d,%d differs from matrix", x, y)
+			}
+		}
+	}
+}